@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: MIT
+
+// Package crontab 将 crontab 文件的内容注册为 scheduled.Server 中的任务
+//
+// 文件格式与 Unix 的 crontab 相同：
+//  # 以 # 开头的行为注释
+//  SHELL=/bin/bash
+//  CRON_TZ=Asia/Shanghai
+//  30 9 * * 1-5 /usr/local/bin/report --daily
+//  @hourly      cleanup
+// 环境变量行对其之后的所有任务有效，其中 CRON_TZ 和 TZ 指定之后的表达式所采用的时区；
+// 任务行由 5 个字段的表达式或是 @ 开头的指令，以及之后的命令字段组成，
+// 表达式的格式可参考 cron.ParseStandard，指令的参数数量由 cron.DirectiveArgs 决定，
+// 比如 @fiscal-year-end 4 close-books 中的 4 为指令的参数。
+// 命令字段同时也是任务的名称，所以同一文件中不能有相同的命令字段。
+package crontab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/issue9/scheduled"
+	"github.com/issue9/scheduled/schedulers"
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+// Entry 表示 crontab 中的一个任务
+type Entry struct {
+	Line    int               // 所在的行号，从 1 开始。
+	Spec    string            // 表达式的内容，包含由 CRON_TZ 指定的时区前缀。
+	Command string            // 命令字段，同时也是任务的名称。
+	Env     map[string]string // 在该任务之前定义的环境变量
+
+	Scheduler schedulers.Scheduler
+}
+
+// Resolver 根据 Entry 获取任务实际执行的函数
+type Resolver func(*Entry) (scheduled.JobFunc, error)
+
+// Parse 分析 crontab 文件的内容
+//
+// 出错时返回的错误信息中包含行号。
+func Parse(r io.Reader) ([]*Entry, error) {
+	entries := make([]*Entry, 0, 10)
+	env := make(map[string]string, 5)
+	names := make(map[string]int, 10)
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		if k, v, ok := parseEnv(text); ok {
+			env[k] = v
+			continue
+		}
+
+		e, err := parseEntry(text, env)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行：%w", line, err)
+		}
+		e.Line = line
+
+		if l, found := names[e.Command]; found {
+			return nil, fmt.Errorf("第 %d 行：与第 %d 行的命令相同", line, l)
+		}
+		names[e.Command] = line
+
+		entries = append(entries, e)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// 分析环境变量行，格式为 name=value，= 两边可以有空格，value 可以用引号包含。
+func parseEnv(text string) (key, val string, ok bool) {
+	index := strings.IndexByte(text, '=')
+	if index <= 0 {
+		return "", "", false
+	}
+
+	key = strings.TrimSpace(text[:index])
+	for i, r := range key {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return "", "", false
+		}
+	}
+
+	val = strings.TrimSpace(text[index+1:])
+	if l := len(val); l >= 2 && (val[0] == '"' || val[0] == '\'') && val[l-1] == val[0] {
+		val = val[1 : l-1]
+	}
+	return key, val, true
+}
+
+func parseEntry(text string, env map[string]string) (*Entry, error) {
+	fs := strings.Fields(text)
+
+	prefix := ""
+	if tz := env["CRON_TZ"]; tz != "" {
+		prefix = "CRON_TZ=" + tz + " "
+	} else if tz := env["TZ"]; tz != "" {
+		prefix = "TZ=" + tz + " "
+	}
+
+	// 指令的参数数量由指令本身决定，可选的参数优先当作参数，无法解析时才当作命令。
+	min, max := 5, 5
+	if fs[0][0] == '@' {
+		var found bool
+		if min, max, found = cron.DirectiveArgs(fs[0]); !found {
+			return nil, errors.New("未找到指令:" + fs[0])
+		}
+		min, max = min+1, max+1
+	}
+	if len(fs) <= min {
+		return nil, errors.New("缺少命令字段")
+	}
+	if max >= len(fs) {
+		max = len(fs) - 1
+	}
+
+	var n int
+	var spec string
+	var s schedulers.Scheduler
+	var err error
+	for n = max; n >= min; n-- {
+		spec = prefix + strings.Join(fs[:n], " ")
+		if s, err = cron.ParseStandard(spec); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Entry{
+		Spec:      spec,
+		Command:   strings.Join(fs[n:], " "),
+		Env:       make(map[string]string, len(env)),
+		Scheduler: s,
+	}
+	for k, v := range env {
+		e.Env[k] = v
+	}
+	return e, nil
+}
+
+// Register 将 entries 注册到 srv
+//
+// 任务的名称为 Entry.Command，执行的函数由 resolve 获取。
+func Register(srv *scheduled.Server, entries []*Entry, resolve Resolver) error {
+	for _, e := range entries {
+		f, err := resolve(e)
+		if err != nil {
+			return fmt.Errorf("第 %d 行：%w", e.Line, err)
+		}
+
+		if err := srv.New(e.Command, f, e.Scheduler, false); err != nil {
+			return fmt.Errorf("第 %d 行：%w", e.Line, err)
+		}
+	}
+	return nil
+}
+
+// Load 分析 r 中的 crontab 内容并注册到 srv
+//
+// 相当于 Parse 与 Register 的组合。
+func Load(srv *scheduled.Server, r io.Reader, resolve Resolver) error {
+	entries, err := Parse(r)
+	if err != nil {
+		return err
+	}
+	return Register(srv, entries, resolve)
+}
+
+// Funcs 返回以命令字段在 funcs 中查找函数的 Resolver
+//
+// 适用于命令字段为任务名称，而不是真实命令的场景，找不到对应的函数时返回错误。
+func Funcs(funcs map[string]scheduled.JobFunc) Resolver {
+	return func(e *Entry) (scheduled.JobFunc, error) {
+		f, found := funcs[e.Command]
+		if !found {
+			return nil, errors.New("未找到任务：" + e.Command)
+		}
+		return f, nil
+	}
+}
+
+// Exec 返回以 shell 执行命令字段的函数，可以直接作为 Resolver 使用。
+//
+// 默认采用 /bin/sh，可以通过环境变量 SHELL 修改；
+// 执行时的环境变量为当前进程的环境变量加上 Entry.Env。
+// 命令以非零值退出时返回错误，其中包含命令的输出内容。
+func Exec(e *Entry) (scheduled.JobFunc, error) {
+	shell := e.Env["SHELL"]
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	env := os.Environ()
+	for k, v := range e.Env {
+		env = append(env, k+"="+v)
+	}
+
+	return func(time.Time) error {
+		cmd := exec.Command(shell, "-c", e.Command)
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+
+package crontab
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled"
+)
+
+const tab = `# 注释
+SHELL=/bin/sh
+GREETING = "hello world"
+
+30 9 * * 1-5 report --daily
+CRON_TZ=Asia/Shanghai
+@hourly      cleanup
+@every 5m    ping
+`
+
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+
+	entries, err := Parse(strings.NewReader(tab))
+	a.NotError(err).Equal(len(entries), 3)
+
+	e := entries[0]
+	a.Equal(e.Line, 5).
+		Equal(e.Spec, "30 9 * * 1-5").
+		Equal(e.Command, "report --daily").
+		Equal(e.Env, map[string]string{"SHELL": "/bin/sh", "GREETING": "hello world"})
+	a.Equal(e.Scheduler.Next(time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)), time.Date(2020, 1, 6, 9, 30, 0, 0, time.UTC))
+
+	e = entries[1]
+	a.Equal(e.Line, 7).
+		Equal(e.Spec, "CRON_TZ=Asia/Shanghai @hourly").
+		Equal(e.Command, "cleanup").
+		Equal(e.Env["CRON_TZ"], "Asia/Shanghai")
+
+	e = entries[2]
+	a.Equal(e.Spec, "CRON_TZ=Asia/Shanghai @every 5m").Equal(e.Command, "ping")
+
+	// 缺少命令
+	_, err = Parse(strings.NewReader("\n30 9 * * 1-5\n"))
+	a.Error(err).True(strings.Contains(err.Error(), "第 2 行"))
+
+	// 无效的表达式
+	_, err = Parse(strings.NewReader("30 25 * * * cmd"))
+	a.Error(err).True(strings.Contains(err.Error(), "第 1 行"))
+
+	// 重复的命令
+	_, err = Parse(strings.NewReader("@daily cmd\n@hourly cmd"))
+	a.Error(err).True(strings.Contains(err.Error(), "第 2 行"))
+
+	// 以 = 开头或是名称无效的行不是环境变量
+	_, err = Parse(strings.NewReader("1x=5 * * * * cmd"))
+	a.Error(err)
+}
+
+func TestParse_directiveArgs(t *testing.T) {
+	a := assert.New(t)
+
+	entries, err := Parse(strings.NewReader("@fiscal-year-end 4 close-books\n@fiscal-year-end archive --all\n@month-end 5 report"))
+	a.NotError(err).Equal(len(entries), 3)
+
+	a.Equal(entries[0].Spec, "@fiscal-year-end 4").Equal(entries[0].Command, "close-books")
+	a.Equal(entries[0].Scheduler.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	// 可选的参数无法解析时，当作命令的一部分。
+	a.Equal(entries[1].Spec, "@fiscal-year-end").Equal(entries[1].Command, "archive --all")
+
+	// 不接受参数的指令
+	a.Equal(entries[2].Spec, "@month-end").Equal(entries[2].Command, "5 report")
+
+	// 缺少参数或命令
+	_, err = Parse(strings.NewReader("@every 5m"))
+	a.Error(err)
+	_, err = Parse(strings.NewReader("@fiscal-year-end"))
+	a.Error(err)
+
+	// 未知的指令
+	_, err = Parse(strings.NewReader("@not-exists cmd"))
+	a.Error(err)
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t)
+	srv := scheduled.NewServer(time.UTC, nil, nil)
+
+	entries, err := Parse(strings.NewReader(tab))
+	a.NotError(err)
+
+	noop := func(time.Time) error { return nil }
+	resolve := Funcs(map[string]scheduled.JobFunc{
+		"report --daily": noop,
+		"cleanup":        noop,
+	})
+	a.Error(Register(srv, entries, resolve)) // 缺少 ping
+
+	srv = scheduled.NewServer(time.UTC, nil, nil)
+	a.NotError(Load(srv, strings.NewReader(tab), Exec))
+	jobs := srv.Jobs()
+	a.Equal(len(jobs), 3)
+	a.Equal(jobs[0].Name(), "cleanup") // 按名称排序
+}
+
+func TestExec(t *testing.T) {
+	a := assert.New(t)
+
+	f, err := Exec(&Entry{Command: `test "$GREETING" = "hello"`, Env: map[string]string{"GREETING": "hello"}})
+	a.NotError(err).NotError(f(time.Now()))
+
+	f, err = Exec(&Entry{Command: "echo failed && exit 1"})
+	a.NotError(err)
+	err = f(time.Now())
+	a.Error(err).True(strings.Contains(err.Error(), "failed"))
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支持的语言
+const (
+	English Locale = "en"
+	Chinese Locale = "zh-CN"
+)
+
+// Locale 格式化时间时采用的语言
+type Locale string
+
+type humanUnit struct {
+	dur    time.Duration
+	en, zh string
+}
+
+// 按从大到小排列
+var humanUnits = []humanUnit{
+	{dur: 24 * time.Hour, en: "d", zh: "天"},
+	{dur: time.Hour, en: "h", zh: "小时"},
+	{dur: time.Minute, en: "m", zh: "分"},
+	{dur: time.Second, en: "s", zh: "秒"},
+}
+
+// Humanize 以英文返回易读的时间段描述
+//
+// 等同于 English.Humanize(d)。
+func Humanize(d time.Duration) string {
+	return English.Humanize(d)
+}
+
+// Humanize 返回易读的时间段描述
+//
+// 最多保留两个相邻的单位，不足一秒的部分会被忽略。
+// 正数表示将来的时间，比如 in 3h12m 或是 3小时12分后；
+// 负数表示过去的时间，比如 5m ago 或是 5分前。
+// 未知的语言按 English 处理。
+func (l Locale) Humanize(d time.Duration) string {
+	past := d < 0
+	if past {
+		d = -d
+	}
+
+	if d < time.Second {
+		if l == Chinese {
+			return "现在"
+		}
+		return "now"
+	}
+
+	var b strings.Builder
+	parts := 0
+	for _, u := range humanUnits {
+		n := d / u.dur
+		if n == 0 {
+			if parts > 0 { // 只保留相邻的单位
+				break
+			}
+			continue
+		}
+
+		b.WriteString(strconv.FormatInt(int64(n), 10))
+		if l == Chinese {
+			b.WriteString(u.zh)
+		} else {
+			b.WriteString(u.en)
+		}
+		d -= n * u.dur
+
+		if parts++; parts == 2 {
+			break
+		}
+	}
+
+	switch {
+	case l == Chinese && past:
+		return b.String() + "前"
+	case l == Chinese:
+		return b.String() + "后"
+	case past:
+		return b.String() + " ago"
+	default:
+		return "in " + b.String()
+	}
+}
+
+// FormatTime 返回本地化的时间格式
+//
+// 未知的语言按 English 处理。
+func (l Locale) FormatTime(t time.Time) string {
+	if l == Chinese {
+		return t.Format("2006年01月02日 15:04:05 MST")
+	}
+	return t.Format("Jan 2, 2006 15:04:05 MST")
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestLocale_Humanize(t *testing.T) {
+	a := assert.New(t)
+
+	data := []struct {
+		dur    time.Duration
+		en, zh string
+	}{
+		{dur: 0, en: "now", zh: "现在"},
+		{dur: 500 * time.Millisecond, en: "now", zh: "现在"},
+		{dur: 42 * time.Second, en: "in 42s", zh: "42秒后"},
+		{dur: -42 * time.Second, en: "42s ago", zh: "42秒前"},
+		{dur: 5*time.Minute + 30*time.Second, en: "in 5m30s", zh: "5分30秒后"},
+		{dur: 3*time.Hour + 12*time.Minute + 10*time.Second, en: "in 3h12m", zh: "3小时12分后"},
+		{dur: time.Hour, en: "in 1h", zh: "1小时后"},
+		{dur: time.Hour + 10*time.Second, en: "in 1h", zh: "1小时后"},
+		{dur: 50*time.Hour + 5*time.Minute, en: "in 2d2h", zh: "2天2小时后"},
+		{dur: -(48*time.Hour + 5*time.Minute), en: "2d ago", zh: "2天前"},
+	}
+
+	for _, item := range data {
+		a.Equal(English.Humanize(item.dur), item.en, "%s 出错，返回值：%s", item.dur, English.Humanize(item.dur))
+		a.Equal(Chinese.Humanize(item.dur), item.zh, "%s 出错，返回值：%s", item.dur, Chinese.Humanize(item.dur))
+		a.Equal(Locale("xx").Humanize(item.dur), item.en)
+	}
+
+	a.Equal(Humanize(42*time.Second), "in 42s")
+}
+
+func TestLocale_FormatTime(t *testing.T) {
+	a := assert.New(t)
+
+	tt := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	a.Equal(English.FormatTime(tt), "Jan 2, 2020 15:04:05 UTC")
+	a.Equal(Chinese.FormatTime(tt), "2020年01月02日 15:04:05 UTC")
+}
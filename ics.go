@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// ICS 中每个任务最多输出的事件数量
+const maxICSEvents = 1000
+
+const icsLayout = "20060102T150405Z"
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// 根据任务名称生成 UID 中的标识
+//
+// 不能使用任务的索引，否则添加或删除任务之后，其它任务的事件 UID 也会改变，
+// 日历客户端会将其当作新的事件。
+func icsID(name string) string {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// ICS 以 iCalendar 格式返回 horizon 时间段内计划执行的任务
+//
+// 方便通过日历客户端订阅任务的执行计划。仅包含已经计算出下一次执行时间的任务，
+// 即需要在 Serve 之后调用才有内容；每个任务最多输出 1000 个事件，
+// 采用 delay 的任务无法预知其执行时长，按计划时间估算。
+// 事件的结束时间由 Job.EstimatedDuration 估算，不足 1 秒的任务不输出结束时间。
+// 事件的描述包含调度算法以及由 l.Humanize 生成的距离执行的时间。
+func (s *Server) ICS(horizon time.Duration, l Locale) string {
+	now := s.now()
+	end := now.Add(horizon)
+	stamp := now.UTC().Format(icsLayout)
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//issue9//scheduled//EN\r\n")
+
+	sep := ", "
+	if l == Chinese {
+		sep = "，"
+	}
+
+	for _, j := range s.Jobs() {
+		id := icsID(j.Name())
+		title := j.Title()
+		dur := j.EstimatedDuration().Round(time.Second)
+		for _, t := range j.upcoming(end, maxICSEvents) {
+			b.WriteString("BEGIN:VEVENT\r\n")
+			fmt.Fprintf(&b, "UID:%s-%d@scheduled\r\n", id, t.Unix())
+			fmt.Fprintf(&b, "DTSTAMP:%s\r\n", stamp)
+			fmt.Fprintf(&b, "DTSTART:%s\r\n", t.UTC().Format(icsLayout))
+			if dur > 0 {
+				fmt.Fprintf(&b, "DTEND:%s\r\n", t.Add(dur).UTC().Format(icsLayout))
+			}
+			fmt.Fprintf(&b, "SUMMARY:%s\r\n", icsEscaper.Replace(j.Name()))
+			fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", icsEscaper.Replace(title+sep+l.Humanize(t.Sub(now))))
+			b.WriteString("END:VEVENT\r\n")
+		}
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
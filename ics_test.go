@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestServer_ICS(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(time.UTC, nil, nil)
+
+	now := time.Now()
+	a.NotError(srv.Tick("tick, 1", succFunc, time.Hour, false, false))
+	a.NotError(srv.At("at", succFunc, now.Add(30*time.Minute), false))
+	a.NotError(srv.At("at-later", succFunc, now.Add(48*time.Hour), false))
+
+	// 未初始化
+	ics := srv.ICS(24*time.Hour, English)
+	a.True(strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n")).
+		True(strings.HasSuffix(ics, "END:VCALENDAR\r\n")).
+		Equal(strings.Count(ics, "BEGIN:VEVENT"), 0)
+
+	for _, j := range srv.jobs {
+		j.init(now)
+	}
+	ics = srv.ICS(24*time.Hour, English)
+	a.Equal(strings.Count(ics, "BEGIN:VEVENT"), 24+1).
+		Equal(strings.Count(ics, "SUMMARY:tick\\, 1\r\n"), 24).
+		Equal(strings.Count(ics, "SUMMARY:at\r\n"), 1).
+		Equal(strings.Count(ics, "SUMMARY:at-later\r\n"), 0).
+		True(strings.Contains(ics, "DTSTART:"+now.Add(time.Hour).UTC().Format(icsLayout)+"\r\n"))
+	a.True(strings.Contains(ics, "DESCRIPTION:"+srv.jobs[1].Title()+"\\, in 29m"), ics)
+
+	ics = srv.ICS(24*time.Hour, Chinese)
+	a.True(strings.Contains(ics, "DESCRIPTION:"+srv.jobs[1].Title()+"，29分"), ics)
+
+	// 最多输出 maxICSEvents 个事件
+	ics = srv.ICS(24*365*time.Hour, English)
+	a.Equal(strings.Count(ics, "SUMMARY:tick\\, 1\r\n"), maxICSEvents)
+}
+
+func TestServer_ICS_uid(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(time.UTC, nil, nil)
+	now := time.Now()
+	at := now.Add(30 * time.Minute)
+
+	a.NotError(srv.At("at", succFunc, at, false))
+	srv.jobs[0].init(now)
+	uid := "UID:" + icsID("at") + "-" + strconv.FormatInt(at.Unix(), 10) + "@scheduled\r\n"
+	a.True(strings.Contains(srv.ICS(time.Hour, English), uid))
+
+	// 添加的任务排在前面，不影响其它任务事件的 UID
+	a.NotError(srv.At("ahead", succFunc, now.Add(10*time.Minute), false))
+	srv.jobs[1].init(now)
+	ics := srv.ICS(time.Hour, English)
+	a.Equal(srv.Jobs()[0].Name(), "ahead").
+		True(strings.Contains(ics, uid)).
+		Equal(strings.Count(ics, "UID:"+icsID("at")), 1)
+
+	a.NotEqual(icsID("at"), icsID("ahead"))
+}
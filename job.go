@@ -3,9 +3,14 @@
 package scheduled
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"reflect"
+	"runtime/debug"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/issue9/scheduled/schedulers"
@@ -21,26 +26,111 @@ const (
 	Failed
 )
 
+// 返回 ErrNotReady 之后首次重新执行的等待时间
+const minBackoff = time.Second
+
+// 计算任务执行时长的指数加权移动平均值时，最新一次执行时长所占的权重
+const durationWeight = 0.2
+
+// 计算退避时间的随机数，rand.Rand 并不是并发安全的，需要 randLocker 保护。
+var (
+	randSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+	randLocker sync.Mutex
+)
+
+// 任务 panic 之后的处理方式
+const (
+	// PanicDefault 采用 Server 的设置，仅对 Job.SetPanicPolicy 有效。
+	PanicDefault PanicPolicy = iota
+
+	// PanicRecover 恢复并继续调度该任务，Server 的默认值。
+	PanicRecover
+
+	// PanicPause 恢复但不再调度该任务。
+	PanicPause
+
+	// PanicPropagate 记录状态之后以 *PanicError 重新抛出 panic，会导致整个进程崩溃，
+	// 适用于需要让 panic 尽早暴露的测试环境。
+	PanicPropagate
+)
+
+// 任务的分类
+const (
+	// ClassBusiness 业务相关的任务，默认值。
+	ClassBusiness Class = iota
+
+	// ClassSystem 系统内部的任务，比如数据清理、心跳等。
+	ClassSystem
+)
+
 // State 状态值类型
 type State int8
 
+// Class 任务的分类
+//
+// 用于区分业务任务与系统内部的任务，方便在展示时过滤掉系统任务。
+type Class int8
+
+// PanicPolicy 任务 panic 之后的处理方式
+type PanicPolicy int8
+
 // JobFunc 每一个定时任务实际上执行的函数签名
+//
+// 参数为该任务计划的执行时间，即使调度有所延迟，传入的依然是计划时间，
+// 方便任务按计划的时间段处理数据。实际的执行时间可通过 time.Now() 获取。
 type JobFunc func(time.Time) error
 
+// Runner 包装任务的执行过程
+//
+// Runner 必须在返回之前调用 f，且仅调用一次。
+// 可以在调用 f 前后调整线程优先级、绑定 cgroup 等，以降低重量级任务对其它任务的影响。
+// 如果在其它 goroutine 中调用 f，需要自行将其中的 panic 传递回当前 goroutine，
+// 否则 PanicPolicy 将无法生效，传递时可以将其包装成 Job 为空的 *PanicError，以保留原始的调用栈。
+type Runner func(f func())
+
 // Job 一个定时任务的基本接口
 type Job struct {
 	schedulers.Scheduler
 
-	name  string
-	f     JobFunc
-	state State
-	err   error // 出错时的错误内容
-	delay bool
+	// 保护以下可变的字段，name 和 delay 在创建之后不会再改变。
+	locker sync.Mutex
+
+	name   string
+	f      JobFunc
+	state  State
+	err    error // 出错时的错误内容
+	delay  bool
+	panic  PanicPolicy
+	runner Runner
+	class  Class
+	window *window // 允许执行的时间段，为空表示不限制。
+
+	transient func(error) bool // 判断错误是否为临时性错误，为空表示采用 Server 的设置。
+	override  *override        // 临时替换的调度器，为空表示未替换。
+	overrides []OverrideRecord // 临时替换调度器的审计记录
+	transform NextTransformer  // 由 Server.SetNextTransformer 指定
 
 	// prev 上次实际上执行的时间
 	// next 下一次可能执行的时间
 	// at 是由调度器在实际调用时的时间。
-	prev, next, at time.Time
+	// planned 在返回 ErrNotReady 之后，原本计划的下一次执行时间。
+	// retry 在退避期间，正在重试的那一次执行原本的计划时间，重试时传递给任务。
+	prev, next, at, planned, retry time.Time
+
+	backoff time.Duration // 返回 ErrNotReady 之后的当前退避时间
+
+	estimate time.Duration // 执行时长的指数加权移动平均值
+}
+
+func (c Class) String() string {
+	switch c {
+	case ClassBusiness:
+		return "business"
+	case ClassSystem:
+		return "system"
+	default:
+		return "<unknown>"
+	}
 }
 
 func (s State) String() string {
@@ -56,6 +146,22 @@ func (s State) String() string {
 	}
 }
 
+// String 返回任务的简短描述
+//
+// 包含名称、调度算法的描述、状态以及距离下一次执行的时间，方便输出到日志。
+func (j *Job) String() string {
+	j.locker.Lock()
+	state, next := j.state, j.next
+	j.locker.Unlock()
+
+	n := "never"
+	if !next.IsZero() {
+		n = Humanize(time.Until(next))
+	}
+
+	return fmt.Sprintf("%s [%s] %s, next %s", j.name, j.Title(), state, n)
+}
+
 // Name 任务的名称
 func (j *Job) Name() string { return j.name }
 
@@ -63,75 +169,418 @@ func (j *Job) Name() string { return j.name }
 //
 // 如果返回值的 IsZero() 为 true，则表示该任务不需要再执行，
 // 一般为 At 之类的一次任务。
-func (j *Job) Next() time.Time { return j.next }
+func (j *Job) Next() time.Time {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.next
+}
 
 // Prev 当前正在执行或是上次执行的时间点
-func (j *Job) Prev() time.Time { return j.prev }
+func (j *Job) Prev() time.Time {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.prev
+}
+
+// Placeholder 是否为尚未绑定处理函数的占位任务
+//
+// 占位任务在通过 Server.Bind 绑定处理函数之前不会被执行。
+func (j *Job) Placeholder() bool {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.f == nil
+}
 
 // State 获取当前的状态
-func (j *Job) State() State { return j.state }
+func (j *Job) State() State {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.state
+}
 
 // Err 返回当前的错误信息
-func (j *Job) Err() error { return j.err }
+func (j *Job) Err() error {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.err
+}
+
+// Class 任务的分类
+func (j *Job) Class() Class {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.class
+}
+
+// SetClass 设置任务的分类
+func (j *Job) SetClass(c Class) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	j.class = c
+}
+
+// ResetError 清除任务的错误信息
+//
+// 一般用于在人工确认错误之后，将 Failed 状态恢复为 Stopped，
+// 以区分新出现的错误和已经确认过的错误。不会影响任务的调度，
+// 正在运行的任务仅清除错误信息，状态保持不变。
+func (j *Job) ResetError() {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	j.err = nil
+	if j.state == Failed {
+		j.state = Stopped
+	}
+}
+
+// EstimatedDuration 任务的预计执行时长
+//
+// 由之前每次执行的时长通过指数加权移动平均计算而来，近期的执行时长所占的比重更大。
+// 返回 ErrNotReady 以及 panic 的执行不计算在内，尚未执行过时返回 0。
+func (j *Job) EstimatedDuration() time.Duration {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.estimate
+}
 
 // Delay 是否在延迟执行
 //
 // 即从任务执行完成的时间点计算下一次执行时间。
 func (j *Job) Delay() bool { return j.delay }
 
+// PanicPolicy 任务 panic 之后的处理方式
+//
+// 返回 PanicDefault 表示采用 Server 的设置。
+func (j *Job) PanicPolicy() PanicPolicy {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return j.panic
+}
+
+// SetPanicPolicy 设置当前任务 panic 之后的处理方式
+//
+// 传递 PanicDefault 表示采用 Server 的设置。
+func (j *Job) SetPanicPolicy(p PanicPolicy) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	j.panic = p
+}
+
+// SetRunner 指定执行当前任务的 Runner
+//
+// 传递 nil 表示直接在调度的 goroutine 中执行。
+func (j *Job) SetRunner(r Runner) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	j.runner = r
+}
+
+// SetTransient 指定当前任务判断错误是否为临时性错误的函数
+//
+// 会覆盖 Server.SetTransient 的设置，传递 nil 表示采用 Server 的设置。
+// 如果需要在 Server 指定了判断函数的情况下，让当前任务在出错时直接失败，
+// 可以传递一个始终返回 false 的函数。
+func (j *Job) SetTransient(f func(error) bool) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	j.transient = f
+}
+
 // 运行当前的任务
 //
+// policy 在任务未指定 panic 处理方式时采用的值；
+// transient 在任务未指定临时性错误的判断函数时采用的值，可以为空；
+// onPanic 在任务 panic 之后的回调函数，可以为空；
 // errlog 在出错时，日志的输出通道，可以为空，表示不输出。
-func (j *Job) run(errlog, infolog *log.Logger) {
+func (j *Job) run(policy PanicPolicy, transient func(error) bool, onPanic func(*PanicError), errlog, infolog *log.Logger) {
+	j.locker.Lock()
+	if j.panic != PanicDefault {
+		policy = j.panic
+	}
+	if j.transient != nil {
+		transient = j.transient
+	}
+	j.state = Running // 由 Server 调用时已经是 Running，此处保证直接调用时的状态也正确。
+	f, next, at, runner := j.f, j.next, j.at, j.runner
+	if !j.retry.IsZero() { // 重试时依然以原本的计划时间执行
+		next = j.retry
+	}
+	j.locker.Unlock()
+
 	defer func() {
-		if msg := recover(); msg != nil {
-			if err, ok := msg.(error); ok {
-				j.err = err
-			} else {
-				j.err = fmt.Errorf("job %s error: %v", j.name, msg)
-			}
+		msg := recover()
+		if msg == nil {
+			return
+		}
 
-			j.state = Failed
+		// 由 Runner 从其它 goroutine 传递过来的 panic，已经包含原始的调用栈。
+		perr, ok := msg.(*PanicError)
+		if !ok || perr.Job != "" {
+			perr = &PanicError{Value: msg, Stack: debug.Stack()}
+		}
+		perr.Job = j.name
+
+		j.locker.Lock()
+		j.err = perr
+		j.state = Failed
 
-			if errlog != nil && j.err != nil {
-				errlog.Println(j.err)
-			}
+		if errlog != nil && j.err != nil {
+			errlog.Println(j.err)
 		}
-	}()
 
-	// 第一条执行语句，保证最快的初始化状态为 Running
-	j.state = Running
+		if policy == PanicPause {
+			j.prev = j.next
+			j.next = time.Time{}
+		} else {
+			j.calcNext()
+		}
+		j.locker.Unlock()
+
+		if onPanic != nil {
+			onPanic(perr)
+		}
+
+		if policy == PanicPropagate {
+			panic(perr)
+		}
+	}()
 
 	if infolog != nil {
-		infolog.Printf("scheduled: start job %s at %s\n", j.Name(), j.at.String())
+		infolog.Printf("scheduled: start job %s at %s\n", j.Name(), at.String())
 	}
 
-	j.err = j.f(j.at)
-	if j.err != nil {
-		j.state = Failed
+	var err error
+	start := time.Now()
+	if runner == nil {
+		err = f(next)
 	} else {
+		runner(func() { err = f(next) })
+	}
+	dur := time.Since(start)
+
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	j.err = err
+	switch {
+	case errors.Is(j.err, ErrNotReady):
+		j.err = nil
 		j.state = Stopped
+		j.calcBackoff(next)
+		return
+	}
+
+	if j.estimate == 0 {
+		j.estimate = dur
+	} else {
+		j.estimate = time.Duration(durationWeight*float64(dur) + (1-durationWeight)*float64(j.estimate))
 	}
 
+	switch {
+	case j.err != nil && transient != nil && transient(j.err):
+		j.state = Failed
+		j.calcBackoff(next)
+		return
+	case j.err != nil:
+		j.state = Failed
+	default:
+		j.state = Stopped
+	}
+
+	j.calcNext()
+}
+
+// 计算下一次的执行时间
+//
+// 调用者需要持有 j.locker，calcBackoff 和 schedulerNext 也是如此。
+func (j *Job) calcNext() {
 	j.prev = j.next
-	if j.Delay() {
-		j.next = j.Scheduler.Next(time.Now().In(j.at.Location()))
+
+	j.retry = time.Time{}
+	if !j.planned.IsZero() { // 退避期间，恢复原本的计划时间
+		j.next = j.planned
+		j.planned = time.Time{}
+		j.backoff = 0
+		return
+	}
+
+	j.next = j.window.fit(j.schedulerNext())
+}
+
+// 在返回 ErrNotReady 或是临时性错误之后计算下一次的执行时间
+//
+// 退避时间从 minBackoff 开始，每次翻倍，直到超过原本的计划时间。
+// 实际的等待时间会在 [backoff/2,backoff] 之间随机选取，
+// 防止因同一个依赖项而未就绪的大量任务在同一时刻重试。
+// slot 为本次执行传递给任务的时间，首次退避时记录下来，之后的重试都会沿用该时间。
+func (j *Job) calcBackoff(slot time.Time) {
+	if j.backoff == 0 {
+		j.planned = j.window.fit(j.schedulerNext())
+		j.retry = slot
+		j.backoff = minBackoff
 	} else {
-		j.next = j.Scheduler.Next(j.at)
+		j.backoff *= 2
+	}
+
+	next := time.Now().In(j.at.Location()).Add(jitter(j.backoff))
+	if !j.planned.IsZero() && !next.Before(j.planned) {
+		j.calcNext()
+		return
+	}
+
+	j.prev = j.next
+	j.next = j.window.fit(next)
+}
+
+// 返回 [d/2,d] 之间的随机值
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	randLocker.Lock()
+	defer randLocker.Unlock()
+	return half + time.Duration(randSource.Int63n(int64(d-half)+1))
+}
+
+// 从调度器中获取下一次的执行时间
+func (j *Job) schedulerNext() time.Time {
+	if j.Delay() {
+		return j.nextAfter(time.Now().In(j.at.Location()))
 	}
+	return j.nextAfter(j.at)
 }
 
 // 初始化当前任务，获取其下次执行时间。
+//
+// 未绑定处理函数的任务，不会计算其执行时间。
 func (j *Job) init(now time.Time) {
-	j.next = j.Scheduler.Next(now)
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	if j.f == nil {
+		j.next = time.Time{}
+		return
+	}
+	j.next = j.window.fit(j.nextAfter(now))
+
+	// 延迟解析的调度器，比如 cron.Lazy，只有在调用 Next 之后才能发现错误。
+	if e, ok := j.Scheduler.(interface{ Err() error }); ok && j.next.IsZero() {
+		if err := e.Err(); err != nil {
+			j.err = err
+			j.state = Failed
+		}
+	}
+}
+
+// 为占位任务绑定处理函数，如果当前任务不是占位任务，返回 false。
+func (j *Job) bind(f JobFunc) bool {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	if j.f != nil {
+		return false
+	}
+	j.f = f
+	return true
+}
+
+// 如果任务需要在 n 时执行，则将其状态设置为 Running 并返回 true。
+//
+// 检测与设置在同一个锁中完成，保证同一任务不会被重复执行。
+func (j *Job) start(n time.Time) bool {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	// 上一次任务还没结束，则跳过该任务
+	if j.state == Running || j.next.IsZero() || j.next.After(n) {
+		return false
+	}
+
+	// 实际执行时间不在允许的时间段内，推迟到下一个时间段。
+	if fit := j.window.fit(n); !fit.Equal(n) {
+		j.next = fit
+		return false
+	}
+
+	j.state = Running
+	j.at = n
+	return true
+}
+
+// 判断任务是否需要在 n 时执行，如果需要，同时返回其计划的执行时间。
+//
+// 与 start 不同，不会改变任务的状态。
+func (j *Job) due(n time.Time) (time.Time, bool) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	if j.state == Running || j.next.IsZero() || j.next.After(n) || !j.window.fit(n).Equal(n) {
+		return time.Time{}, false
+	}
+	return j.next, true
+}
+
+// 跳过在 n 时的执行，直接计算下一次的执行时间。
+func (j *Job) skip(n time.Time) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	prev := j.prev
+	j.at = n
+	j.calcNext()
+	j.prev = prev // 并未实际执行，保持原值。
+}
+
+// NextN 返回从 Next() 开始的 n 个执行时间
+//
+// 会考虑 OnlyBetween 以及 Server.OverrideSchedule 的设置，
+// 调度终结或是任务尚未开始调度时，返回的数量会少于 n。
+// 与 Next 相同，并不会考虑任务执行时长等因素，仅供参考。
+//
+// 调用 Next 会改变状态的调度器，参考 schedulers.Stateful，无法预知之后的时间，仅返回 Next()。
+func (j *Job) NextN(n int) []time.Time {
+	return j.upcoming(time.Time{}, n)
+}
+
+// 返回 [Next(), end) 之间的执行时间，最多 max 个，end 为零值表示不限制。
+func (j *Job) upcoming(end time.Time, max int) []time.Time {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	if max <= 0 {
+		return nil
+	}
+
+	stateful := schedulers.IsStateful(j.Scheduler) || (j.override != nil && schedulers.IsStateful(j.override.scheduler))
+
+	ret := make([]time.Time, 0, 10)
+	for t := j.next; len(ret) < max && !t.IsZero() && (end.IsZero() || t.Before(end)); t = j.window.fit(j.nextAfter(t)) {
+		ret = append(ret, t)
+		if stateful { // 不能调用 Next，仅返回已经计算好的时间。
+			break
+		}
+	}
+	return ret
+}
+
+// 调度时任务状态的快照
+type snapshot struct {
+	job   *Job
+	next  time.Time
+	state State
 }
 
-func sortJobs(jobs []*Job) {
+func (j *Job) snapshot() snapshot {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	return snapshot{job: j, next: j.next, state: j.state}
+}
+
+func sortJobs(jobs []snapshot) {
 	sort.SliceStable(jobs, func(i, j int) bool {
-		if jobs[i].next.IsZero() || jobs[i].State() == Running {
+		if jobs[i].next.IsZero() || jobs[i].state == Running {
 			return false
 		}
-		if jobs[j].next.IsZero() || jobs[j].State() == Running {
+		if jobs[j].next.IsZero() || jobs[j].state == Running {
 			return true
 		}
 		return jobs[i].next.Before(jobs[j].next)
@@ -140,8 +589,10 @@ func sortJobs(jobs []*Job) {
 
 // Jobs 返回所有注册的任务
 func (s *Server) Jobs() []*Job {
+	s.locker.Lock()
 	jobs := make([]*Job, 0, len(s.jobs))
 	jobs = append(jobs, s.jobs...)
+	s.locker.Unlock()
 
 	sort.SliceStable(jobs, func(i, j int) bool {
 		return jobs[i].name < jobs[j].name
@@ -150,52 +601,166 @@ func (s *Server) Jobs() []*Job {
 	return jobs
 }
 
+// JobsOf 返回所有分类为 c 的任务
+func (s *Server) JobsOf(c Class) []*Job {
+	jobs := s.Jobs()
+	ret := jobs[:0]
+	for _, j := range jobs {
+		if j.Class() == c {
+			ret = append(ret, j)
+		}
+	}
+	return ret
+}
+
 // Tick 添加一个新的定时任务
 func (s *Server) Tick(name string, f JobFunc, dur time.Duration, imm, delay bool) error {
 	scheduler, err := ticker.New(dur, imm)
-	if err == nil {
-		s.New(name, f, scheduler, delay)
+	if err != nil {
+		return err
 	}
-	return err
+	return s.New(name, f, scheduler, delay)
 }
 
 // Cron 使用 cron 表达式新建一个定时任务
 //
-// 具体文件可以参考 schedulers/cron.Parse
+// 具体文件可以参考 schedulers/cron.Parse，表达式中的 H 以 name 作为键名计算。
 func (s *Server) Cron(name string, f JobFunc, spec string, delay bool) error {
-	scheduler, err := cron.Parse(spec)
-	if err == nil {
-		s.New(name, f, scheduler, delay)
+	var opts []cron.Option
+	if name != "" { // 指令会忽略 Hash，所以总是可以指定。
+		opts = append(opts, cron.Hash(name))
 	}
-	return err
+
+	scheduler, err := cron.Parse(spec, opts...)
+	if err != nil {
+		return err
+	}
+	return s.New(name, f, scheduler, delay)
 }
 
 // At 添加 At 类型的定时器
 //
 // 具体文件可以参考 schedulers/at.At
 func (s *Server) At(name string, f JobFunc, t time.Time, delay bool) error {
-	s.New(name, f, at.At(t), delay)
-	return nil // 保持返回参数与其它函数相同
+	return s.New(name, f, at.At(t), delay)
 }
 
 // New 添加一个新的定时任务
 //
 // name 作为定时任务的一个简短描述，不作唯一要求；
 // delay 是否从任务执行完之后，才开始计算下个执行的时间点。
-func (s *Server) New(name string, f JobFunc, scheduler schedulers.Scheduler, delay bool) {
+//
+// f 为空时返回 ErrNilJobFunc，scheduler 为空时返回 ErrNilScheduler。
+func (s *Server) New(name string, f JobFunc, scheduler schedulers.Scheduler, delay bool) error {
+	if f == nil {
+		return ErrNilJobFunc
+	}
+	return s.add(name, f, scheduler, delay)
+}
+
+// Placeholder 添加一个尚未绑定处理函数的定时任务
+//
+// 该任务在通过 Bind 绑定处理函数之前不会被执行，
+// 其它参数与 New 相同。
+func (s *Server) Placeholder(name string, scheduler schedulers.Scheduler, delay bool) error {
+	return s.add(name, nil, scheduler, delay)
+}
+
+// Bind 为名称为 name 的占位任务绑定处理函数
+//
+// 所有名称为 name 且尚未绑定处理函数的任务都会被绑定，
+// 如果不存在这样的任务，则返回 ErrJobNotFound。
+func (s *Server) Bind(name string, f JobFunc) error {
+	if f == nil {
+		return ErrNilJobFunc
+	}
+
+	s.locker.Lock()
+	found := false
+	for _, job := range s.jobs {
+		if job.name != name || !job.bind(f) {
+			continue
+		}
+
+		found = true
+		if s.running {
+			job.init(s.now())
+		}
+	}
+	s.locker.Unlock()
+
+	if !found {
+		return ErrJobNotFound
+	}
+
+	s.reschedule()
+	return nil
+}
+
+// 判断 s 是否为 nil
+//
+// 除了 nil 接口，值为 nil 的指针（比如 (*T)(nil)）也视为 nil。
+func isNilScheduler(s schedulers.Scheduler) bool {
+	if s == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(s)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func (s *Server) add(name string, f JobFunc, scheduler schedulers.Scheduler, delay bool) error {
+	if isNilScheduler(scheduler) {
+		return ErrNilScheduler
+	}
+
 	job := &Job{
 		Scheduler: scheduler,
 		name:      name,
 		f:         f,
 		delay:     delay,
 	}
+	s.locker.Lock()
+	job.transform = s.transform
 	s.jobs = append(s.jobs, job)
-
-	// 服务已经运行，则需要触发调度任务。
 	if s.running {
 		job.init(s.now())
-		if len(s.nextScheduled) == 0 {
-			s.nextScheduled <- struct{}{}
+	}
+	s.locker.Unlock()
+
+	s.reschedule()
+
+	return nil
+}
+
+// 服务已经运行，则需要触发调度任务。
+func (s *Server) reschedule() {
+	s.locker.Lock()
+	running := s.running
+	s.locker.Unlock()
+
+	if !running {
+		return
+	}
+
+	select {
+	case s.nextScheduled <- struct{}{}:
+	default: // 已经有等待中的调度请求
+	}
+}
+
+// Acknowledge 确认名为 name 的任务的错误信息
+//
+// 功能与 Job.ResetError 相同，如果任务不存在，则返回 ErrJobNotFound。
+func (s *Server) Acknowledge(name string) error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	for _, job := range s.jobs {
+		if job.name == name {
+			job.ResetError()
+			return nil
 		}
 	}
+	return ErrJobNotFound
 }
@@ -4,6 +4,7 @@ package scheduled
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"testing"
@@ -12,6 +13,8 @@ import (
 	"github.com/issue9/assert"
 
 	"github.com/issue9/scheduled/schedulers"
+	"github.com/issue9/scheduled/schedulers/adaptive"
+	"github.com/issue9/scheduled/schedulers/cron"
 	"github.com/issue9/scheduled/schedulers/ticker"
 )
 
@@ -41,6 +44,19 @@ var (
 	errlog = log.New(ioutil.Discard, "ERRO", 0)
 )
 
+// 返回只包含一个已初始化任务的 Server 及该任务，任务由每秒执行一次的 ticker 调度。
+func newTestJob(a *assert.Assertion, name string, f JobFunc) (*Server, *Job) {
+	srv := NewServer(nil, nil, nil)
+
+	s, err := ticker.New(time.Second, false)
+	a.NotError(err).NotNil(s)
+	a.NotError(srv.New(name, f, s, false))
+
+	j := srv.jobs[0]
+	j.init(time.Now())
+	return srv, j
+}
+
 func TestJob_run(t *testing.T) {
 	a := assert.New(t)
 	now := time.Now()
@@ -58,7 +74,7 @@ func TestJob_run(t *testing.T) {
 		at:        now,
 	}
 	j.init(now)
-	j.run(nil, nil)
+	j.run(PanicRecover, nil, nil, nil, nil)
 	a.Nil(j.Err()).
 		Equal(j.State(), Stopped).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
@@ -70,7 +86,7 @@ func TestJob_run(t *testing.T) {
 		at:        now,
 	}
 	j.init(now)
-	j.run(errlog, nil)
+	j.run(PanicRecover, nil, nil, errlog, nil)
 	a.NotNil(j.Err()).
 		Equal(j.State(), Failed).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
@@ -82,7 +98,7 @@ func TestJob_run(t *testing.T) {
 		at:        now,
 	}
 	j.init(now)
-	j.run(nil, nil)
+	j.run(PanicRecover, nil, nil, nil, nil)
 	a.NotNil(j.Err()).
 		Equal(j.State(), Failed).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
@@ -96,7 +112,7 @@ func TestJob_run(t *testing.T) {
 		at:        now,
 	}
 	j.init(now)
-	j.run(nil, nil)
+	j.run(PanicRecover, nil, nil, nil, nil)
 	a.Nil(j.Err()).
 		Equal(j.State(), Stopped).
 		Equal(j.Next().Unix(), now.Add(3*time.Second).Unix()) // delayFunc 延时两秒
@@ -110,12 +126,234 @@ func TestJob_run(t *testing.T) {
 		at:        now,
 	}
 	j.init(now)
-	j.run(nil, nil)
+	j.run(PanicRecover, nil, nil, nil, nil)
 	a.Nil(j.Err()).
 		Equal(j.State(), Stopped).
 		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
 }
 
+func TestJob_run_scheduledAt(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	s, err := ticker.New(time.Minute, false)
+	a.NotError(err).NotNil(s)
+
+	var scheduledAt time.Time
+	j := &Job{
+		name: "scheduled-at",
+		f: func(t time.Time) error {
+			scheduledAt = t
+			return nil
+		},
+		Scheduler: s,
+	}
+	j.init(now)
+	j.at = now.Add(90 * time.Second) // 调度延迟
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(scheduledAt, now.Add(time.Minute))
+}
+
+func TestJob_run_panicPolicy(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	newJob := func() *Job {
+		s, err := ticker.New(time.Second, false)
+		a.NotError(err).NotNil(s)
+		j := &Job{
+			name:      "fail",
+			f:         failFunc,
+			Scheduler: s,
+			at:        now,
+		}
+		j.init(now)
+		return j
+	}
+
+	// PanicPause
+	j := newJob()
+	j.run(PanicPause, nil, nil, nil, nil)
+	a.NotNil(j.Err()).
+		Equal(j.State(), Failed).
+		True(j.Next().IsZero())
+
+	// PanicPropagate
+	j = newJob()
+	a.Panic(func() {
+		j.run(PanicPropagate, nil, nil, nil, nil)
+	})
+	a.NotNil(j.Err()).
+		Equal(j.State(), Failed).
+		Equal(j.Next().Unix(), now.Add(1*time.Second).Unix())
+
+	// 任务的设置优先于 Server 的设置
+	j = newJob()
+	j.SetPanicPolicy(PanicPause)
+	a.Equal(j.PanicPolicy(), PanicPause)
+	a.NotPanic(func() {
+		j.run(PanicPropagate, nil, nil, nil, nil)
+	})
+	a.True(j.Next().IsZero())
+}
+
+func TestJob_run_notReady(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	s, err := ticker.New(time.Minute, false)
+	a.NotError(err).NotNil(s)
+
+	ready := false
+	var got []time.Time
+	j := &Job{
+		name: "not-ready",
+		f: func(t time.Time) error {
+			got = append(got, t)
+			if ready {
+				return nil
+			}
+			return fmt.Errorf("wrap: %w", ErrNotReady)
+		},
+		Scheduler: s,
+		at:        now,
+	}
+	j.init(now)
+	planned := now.Add(time.Minute)
+
+	// 执行一次，其下一次执行时间在 [backoff/2,backoff] 之间。
+	//
+	// 退避时间以执行时的当前时间为基准，所以在执行前后各取一次时间作为上下限，
+	// 不受测试执行速度的影响。
+	runInBackoff := func(backoff time.Duration) {
+		before := time.Now()
+		j.run(PanicRecover, nil, nil, nil, nil)
+		after := time.Now()
+
+		next := j.Next()
+		a.False(next.Before(before.Add(backoff/2)), "%s 小于 %s/2", next.Sub(before), backoff).
+			False(next.After(after.Add(backoff)), "%s 大于 %s", next.Sub(after), backoff)
+	}
+
+	for _, backoff := range []time.Duration{1, 2, 4, 8, 16, 32} {
+		runInBackoff(backoff * time.Second)
+		a.Nil(j.Err()).Equal(j.State(), Stopped)
+	}
+
+	// 重试时传递的依然是原本的计划时间
+	a.Equal(len(got), 6)
+	for _, t := range got {
+		a.True(t.Equal(got[0]), t, got[0])
+	}
+	a.NotEqual(j.Next(), got[0])
+
+	// 超过原本的计划时间，64 秒的退避时间可能随机到 60 秒之前。
+	for i := 0; i < 2 && !j.Next().Equal(planned); i++ {
+		j.run(PanicRecover, nil, nil, nil, nil)
+	}
+	a.True(j.Next().Equal(planned), j.Next(), planned)
+
+	// 退避期间正常执行，恢复原本的计划时间
+	runInBackoff(time.Second)
+	ready = true
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Nil(j.Err()).
+		Equal(j.State(), Stopped).
+		True(j.Next().Equal(planned)).
+		True(got[len(got)-1].Equal(planned)).
+		True(j.retry.IsZero())
+}
+
+func TestJob_run_transient(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	s, err := ticker.New(time.Hour, false)
+	a.NotError(err).NotNil(s)
+
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+	isTransient := func(err error) bool { return errors.Is(err, errTransient) }
+
+	var ret error
+	var got []time.Time
+	j := &Job{
+		name: "transient",
+		f: func(t time.Time) error {
+			got = append(got, t)
+			return ret
+		},
+		Scheduler: s,
+		at:        now,
+	}
+	j.init(now)
+	planned := now.Add(time.Hour)
+
+	// 临时性错误，提前重试。
+	ret = errTransient
+	j.run(PanicRecover, isTransient, nil, nil, nil)
+	a.Equal(j.Err(), errTransient).
+		Equal(j.State(), Failed).
+		True(j.Next().Before(now.Add(time.Minute)))
+
+	// 重试时传递的依然是原本的计划时间
+	j.run(PanicRecover, isTransient, nil, nil, nil)
+	a.Equal(j.State(), Failed).
+		True(j.Next().Before(now.Add(time.Minute))).
+		Equal(len(got), 2).
+		True(got[1].Equal(got[0])).
+		False(got[1].Equal(j.prev))
+
+	// 恢复之后回到原本的计划时间
+	ret = nil
+	j.run(PanicRecover, isTransient, nil, nil, nil)
+	a.Nil(j.Err()).
+		Equal(j.State(), Stopped).
+		Equal(j.Next().Unix(), planned.Unix()).
+		True(got[2].Equal(got[0]))
+
+	// 永久性错误，直接失败。
+	ret = errPermanent
+	j.run(PanicRecover, isTransient, nil, nil, nil)
+	a.Equal(j.Err(), errPermanent).
+		Equal(j.State(), Failed).
+		Equal(j.Next().Unix(), planned.Unix())
+
+	// 任务的设置优先于 Server 的设置
+	j.SetTransient(func(error) bool { return false })
+	ret = errTransient
+	j.run(PanicRecover, isTransient, nil, nil, nil)
+	a.Equal(j.Err(), errTransient).
+		Equal(j.Next().Unix(), planned.Unix())
+}
+
+func TestJitter(t *testing.T) {
+	a := assert.New(t)
+
+	for i := 0; i < 100; i++ {
+		d := jitter(time.Second)
+		a.True(d >= 500*time.Millisecond && d <= time.Second, d)
+	}
+	a.Equal(jitter(0), 0)
+}
+
+func TestJob_String(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := ticker.New(time.Hour, false)
+	a.NotError(err).NotNil(s)
+
+	j := &Job{
+		name:      "tick",
+		f:         succFunc,
+		Scheduler: s,
+	}
+	a.Equal(j.String(), "tick [每隔 1h0m0s] stopped, next never")
+
+	j.next = time.Now().Add(time.Hour + 30*time.Second)
+	a.Equal(j.String(), "tick [每隔 1h0m0s] stopped, next in 1h")
+}
+
 func TestSortJobs(t *testing.T) {
 	a := assert.New(t)
 
@@ -148,10 +386,15 @@ func TestSortJobs(t *testing.T) {
 		},
 	}
 
-	sortJobs(jobs)
-	a.Equal(jobs[0].name, "3").
-		Equal(jobs[1].name, "5").
-		Equal(jobs[2].name, "1")
+	snapshots := make([]snapshot, 0, len(jobs))
+	for _, j := range jobs {
+		snapshots = append(snapshots, j.snapshot())
+	}
+
+	sortJobs(snapshots)
+	a.Equal(snapshots[0].job.name, "3").
+		Equal(snapshots[1].job.name, "5").
+		Equal(snapshots[2].job.name, "1")
 }
 
 func TestServer_Jobs(t *testing.T) {
@@ -168,10 +411,201 @@ func TestServer_Jobs(t *testing.T) {
 	a.Equal(len(jobs), len(srv.jobs))
 }
 
+func TestServer_JobsOf(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+
+	now := time.Now()
+	a.NotError(srv.At("j1", succFunc, now, false))
+	a.NotError(srv.At("j2", succFunc, now, false))
+	a.NotError(srv.At("j3", succFunc, now, false))
+	srv.jobs[1].SetClass(ClassSystem)
+	a.Equal(srv.jobs[1].Class(), ClassSystem)
+	a.Equal(srv.jobs[0].Class(), ClassBusiness)
+
+	jobs := srv.JobsOf(ClassBusiness)
+	a.Equal(len(jobs), 2).
+		Equal(jobs[0].Name(), "j1").
+		Equal(jobs[1].Name(), "j3")
+
+	jobs = srv.JobsOf(ClassSystem)
+	a.Equal(len(jobs), 1).Equal(jobs[0].Name(), "j2")
+
+	a.Equal(ClassSystem.String(), "system")
+	a.Equal(Class(100).String(), "<unknown>")
+}
+
 func TestServer_NewCron(t *testing.T) {
 	a := assert.New(t)
 
 	srv := NewServer(nil, nil, nil)
-	a.NotError(srv.Cron("test", nil, "* * * 3-7 * *", false))
-	a.Error(srv.Cron("test", nil, "* * * 3-7a * *", false))
+	a.NotError(srv.Cron("test", succFunc, "* * * 3-7 * *", false))
+	a.Error(srv.Cron("test", succFunc, "* * * 3-7a * *", false))
+	a.Equal(srv.Cron("test", nil, "* * * 3-7 * *", false), ErrNilJobFunc)
+
+	// H 以任务名称作为键名
+	a.NotError(srv.Cron("hash", succFunc, "H H * * * *", false))
+	a.NotError(srv.Cron("reboot", succFunc, "@reboot", false))
+	a.NotError(srv.Cron("thu", succFunc, "0 0 0 * * THU", false))
+
+	// 时区名称中的 H 不会被当作表达式中的 H
+	a.NotError(srv.Cron("ho-chi-minh", succFunc, "CRON_TZ=Asia/Ho_Chi_Minh @every 1h", false))
+	a.NotError(srv.Cron("halifax", succFunc, "TZ=America/Halifax @reboot", false))
+	a.NotError(srv.Cron("helsinki", succFunc, "CRON_TZ=Europe/Helsinki @month-end", false))
+
+	// 多个表达式
+	a.NotError(srv.Cron("backup", succFunc, "0 0 3 * * 1-5; 0 0 12 * * 6", false))
+}
+
+func TestServer_New(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+
+	s, err := ticker.New(time.Second, false)
+	a.NotError(err).NotNil(s)
+
+	a.Equal(srv.New("nil-f", nil, s, false), ErrNilJobFunc)
+	a.Equal(srv.New("nil-scheduler", succFunc, nil, false), ErrNilScheduler)
+	var nilScheduler *adaptive.Scheduler
+	a.Equal(srv.New("typed-nil-scheduler", succFunc, nilScheduler, false), ErrNilScheduler)
+	var nilIncr *incr // Title 不访问接收者
+	a.Equal(srv.New("typed-nil-incr", succFunc, nilIncr, false), ErrNilScheduler)
+	a.Empty(srv.jobs)
+
+	a.NotError(srv.New("succ", succFunc, s, false))
+	a.Equal(len(srv.jobs), 1)
+}
+
+func TestServer_Placeholder(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+
+	s, err := ticker.New(time.Second, false)
+	a.NotError(err).NotNil(s)
+
+	a.Equal(srv.Placeholder("p", nil, false), ErrNilScheduler)
+	a.NotError(srv.Placeholder("p", s, false))
+	a.True(srv.jobs[0].Placeholder())
+
+	// 占位任务不会计算执行时间
+	srv.jobs[0].init(time.Now())
+	a.True(srv.jobs[0].Next().IsZero())
+
+	a.Equal(srv.Bind("p", nil), ErrNilJobFunc)
+	a.Equal(srv.Bind("not-exists", succFunc), ErrJobNotFound)
+	a.NotError(srv.Bind("p", succFunc))
+	a.False(srv.jobs[0].Placeholder())
+	a.Equal(srv.Bind("p", succFunc), ErrJobNotFound) // 已经绑定
+
+	srv.jobs[0].init(time.Now())
+	a.False(srv.jobs[0].Next().IsZero())
+}
+
+func TestJob_init_lazy(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+
+	s, err := cron.Lazy("0 0 25 * * *")
+	a.NotError(err).NotNil(s)
+	a.NotError(srv.New("lazy", succFunc, s, false))
+
+	j := srv.jobs[0]
+	a.NotError(j.Err()).Equal(j.State(), Stopped)
+
+	j.init(time.Now())
+	a.True(j.Next().IsZero())
+	a.Error(j.Err()).Equal(j.State(), Failed)
+}
+
+func TestJob_SetRunner(t *testing.T) {
+	a := assert.New(t)
+	_, j := newTestJob(a, "succ", succFunc)
+
+	count := 0
+	j.SetRunner(func(f func()) {
+		count++
+		f()
+	})
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(count, 1).Equal(j.State(), Stopped)
+
+	j.SetRunner(nil)
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(count, 1).Equal(j.State(), Stopped)
+}
+
+func TestServer_Acknowledge(t *testing.T) {
+	a := assert.New(t)
+	srv, j := newTestJob(a, "erro", erroFunc)
+
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(j.State(), Failed).Error(j.Err())
+	next := j.Next()
+
+	a.Equal(srv.Acknowledge("not-exists"), ErrJobNotFound)
+	a.NotError(srv.Acknowledge("erro"))
+	a.Equal(j.State(), Stopped).NotError(j.Err())
+	a.Equal(j.Next(), next) // 不影响调度
+
+	// 再次出错
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(j.State(), Failed).Error(j.Err())
+	j.ResetError()
+	a.Equal(j.State(), Stopped).NotError(j.Err())
+}
+
+func TestJob_EstimatedDuration(t *testing.T) {
+	a := assert.New(t)
+
+	dur := 100 * time.Millisecond
+	_, j := newTestJob(a, "sleep", func(time.Time) error {
+		time.Sleep(dur)
+		return nil
+	})
+	a.Equal(j.EstimatedDuration(), 0)
+
+	j.run(PanicRecover, nil, nil, nil, nil)
+	first := j.EstimatedDuration()
+	a.True(first >= dur, first)
+
+	// 执行时长变短之后，预计时长向其靠拢，但不会立即等于该值。
+	dur = 0
+	j.run(PanicRecover, nil, nil, nil, nil)
+	second := j.EstimatedDuration()
+	a.True(second < first, second).True(second > first/2, second)
+}
+
+func TestJob_NextN(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := ticker.New(time.Hour, false)
+	a.NotError(err).NotNil(s)
+	j := &Job{name: "next-n", f: func(time.Time) error { return nil }, Scheduler: s}
+
+	// 尚未开始调度
+	a.Empty(j.NextN(3))
+
+	j.init(now)
+	a.Equal(j.NextN(3), []time.Time{now.Add(time.Hour), now.Add(2 * time.Hour), now.Add(3 * time.Hour)})
+	a.Empty(j.NextN(0))
+
+	// 预览不会消耗 adaptive 提交的结果
+	as, err := adaptive.New(time.Minute, func(adaptive.Result, time.Duration) time.Duration { return time.Hour })
+	a.NotError(err).NotNil(as)
+	j = &Job{name: "adaptive", f: func(time.Time) error { return nil }, Scheduler: as}
+	j.init(now)
+	as.Report(nil)
+	a.Equal(j.NextN(3), []time.Time{now.Add(time.Minute)})
+	a.Equal(as.Interval(), time.Minute)
+	a.Equal(as.Next(now), now.Add(time.Hour))
+
+	// 开始调度之前的预览不会消耗立即执行的那一次
+	imm, err := ticker.New(time.Hour, true)
+	a.NotError(err).NotNil(imm)
+	a.Nil(schedulers.NextN(imm, now, 3))
+	j = &Job{name: "imm", f: func(time.Time) error { return nil }, Scheduler: imm}
+	before := time.Now()
+	j.init(now)
+	a.False(j.Next().Before(before)).False(j.Next().After(time.Now()))
 }
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"log"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+)
+
+// Nice 返回以指定 nice 值执行任务的 Runner
+//
+// 任务会在一个独占的系统线程中执行，该线程的 nice 值被设置为 n，
+// 任务结束之后该线程随之销毁，不会影响其它 goroutine。
+// 非特权用户只能调高 nice 值，设置失败时依然会以原有的优先级执行任务，
+// 错误信息输出到 errlog，errlog 为空表示不输出。
+//
+// 任务中的 panic 会以 *PanicError 的形式在调用者所在的 goroutine 中重新抛出，
+// 其中包含任务所在线程的调用栈。
+//
+// 仅在 Linux 下可用，Linux 的线程优先级是按线程计算的，其它系统并不支持此方式。
+func Nice(n int, errlog *log.Logger) Runner {
+	return func(f func()) {
+		done := make(chan *PanicError)
+
+		go func() {
+			defer func() {
+				if msg := recover(); msg != nil {
+					done <- &PanicError{Value: msg, Stack: debug.Stack()}
+					return
+				}
+				done <- nil
+			}()
+
+			// 不调用 UnlockOSThread，goroutine 结束时，该线程也随之销毁，
+			// 修改过优先级的线程不会再被其它 goroutine 使用。
+			runtime.LockOSThread()
+			if err := syscall.Setpriority(syscall.PRIO_PROCESS, syscall.Gettid(), n); err != nil && errlog != nil {
+				errlog.Printf("scheduled: set nice %d: %s\n", n, err)
+			}
+			f()
+		}()
+
+		if err := <-done; err != nil { // 在当前 goroutine 中重新抛出
+			panic(err)
+		}
+	}
+}
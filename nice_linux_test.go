@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestNice(t *testing.T) {
+	a := assert.New(t)
+
+	prio := -1
+	Nice(19, nil)(func() {
+		// getpriority 系统调用返回的是 20-nice
+		p, err := syscall.Getpriority(syscall.PRIO_PROCESS, syscall.Gettid())
+		a.NotError(err)
+		prio = 20 - p
+	})
+	a.Equal(prio, 19)
+
+	// panic 以 *PanicError 传递到调用者，包含原始的调用栈。
+	func() {
+		defer func() {
+			perr, ok := recover().(*PanicError)
+			a.True(ok).
+				Equal(perr.Value, "panic").
+				Empty(perr.Job).
+				True(strings.Contains(string(perr.Stack), "nice_linux_test.go"))
+		}()
+		Nice(19, nil)(func() { panic("panic") })
+	}()
+
+	// 非特权用户无法调低 nice 值
+	if os.Geteuid() != 0 {
+		buf := new(bytes.Buffer)
+		Nice(-5, log.New(buf, "", 0))(func() {})
+		a.True(strings.Contains(buf.String(), "set nice -5"))
+	}
+
+	// 与 Job 结合使用
+	_, j := newTestJob(a, "fail", failFunc)
+	j.SetRunner(Nice(19, nil))
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(j.State(), Failed).Error(j.Err())
+	perr, ok := j.Err().(*PanicError)
+	a.True(ok).
+		Equal(perr.Job, "fail").
+		Equal(perr.Value, "fail").
+		True(strings.Contains(string(perr.Stack), "nice_linux.go"))
+}
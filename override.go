@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"errors"
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// 任务被临时替换的调度器
+type override struct {
+	scheduler schedulers.Scheduler
+	until     time.Time // 临时调度器的截止时间，之后恢复原来的调度器。
+}
+
+// 每个任务最多保留的 OverrideRecord 数量，超出时丢弃最早的记录。
+const maxOverrideRecords = 100
+
+// OverrideRecord 临时替换调度器的审计记录
+type OverrideRecord struct {
+	By        string               // 操作者，由 Server.OverrideScheduleBy 指定。
+	Scheduler schedulers.Scheduler // 临时使用的调度器
+	Until     time.Time            // 临时调度器的截止时间
+	At        time.Time            // 执行替换操作的时间
+}
+
+// OverrideRecords 返回当前任务临时替换调度器的记录
+//
+// 按操作时间排序，最早的在前，最多保留最近的 100 条记录。
+func (j *Job) OverrideRecords() []OverrideRecord {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	records := make([]OverrideRecord, len(j.overrides))
+	copy(records, j.overrides)
+	return records
+}
+
+// Override 返回当前任务临时使用的调度器及其截止时间
+//
+// 未被临时替换或是已经超过截止时间，返回 nil。
+func (j *Job) Override() (schedulers.Scheduler, time.Time) {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+
+	if j.override == nil || !time.Now().Before(j.override.until) {
+		return nil, time.Time{}
+	}
+	return j.override.scheduler, j.override.until
+}
+
+// 从 last 开始计算下一次的执行时间，会考虑临时替换的调度器以及 NextTransformer。
+//
+// 临时调度器只在截止时间之前有效，之后的时间依然由原来的调度器决定。
+// 调用者需要持有 j.locker。
+func (j *Job) nextAfter(last time.Time) time.Time {
+	next := j.schedulerNextAfter(last)
+	if j.transform == nil || next.IsZero() {
+		return next
+	}
+
+	if t := j.transform(j, next); t.IsZero() || t.After(last) {
+		return t
+	}
+	return next
+}
+
+func (j *Job) schedulerNextAfter(last time.Time) time.Time {
+	if o := j.override; o != nil && last.Before(o.until) {
+		if next := o.scheduler.Next(last); !next.IsZero() && next.Before(o.until) {
+			return next
+		}
+		last = o.until.Add(-1) // 保证截止时间本身也可以被原来的调度器选中
+	}
+	return j.Scheduler.Next(last)
+}
+
+// OverrideSchedule 在 until 之前临时以 scheduler 替换名为 name 的任务的调度器
+//
+// 比如在故障期间临时将任务改为每 5 分钟执行一次，到达 until 之后自动恢复原来的调度器。
+// 替换立即生效，正在执行的任务在结束之后才会按新的调度器计算执行时间。
+// 重复调用会覆盖之前的设置，操作会记录在 infolog 以及 Job.OverrideRecords 中。
+func (s *Server) OverrideSchedule(name string, scheduler schedulers.Scheduler, until time.Time) error {
+	return s.OverrideScheduleBy("", name, scheduler, until)
+}
+
+// OverrideScheduleBy 与 OverrideSchedule 相同，by 表示操作者，会记录在审计记录中。
+func (s *Server) OverrideScheduleBy(by, name string, scheduler schedulers.Scheduler, until time.Time) error {
+	if isNilScheduler(scheduler) {
+		return ErrNilScheduler
+	}
+
+	now := s.now()
+	if !until.After(now) {
+		return errors.New("参数 until 必须晚于当前时间")
+	}
+
+	s.locker.Lock()
+	var job *Job
+	for _, j := range s.jobs {
+		if j.name == name {
+			job = j
+			break
+		}
+	}
+	if job == nil {
+		s.locker.Unlock()
+		return ErrJobNotFound
+	}
+
+	job.locker.Lock()
+	job.override = &override{scheduler: scheduler, until: until}
+	job.overrides = append(job.overrides, OverrideRecord{By: by, Scheduler: scheduler, Until: until, At: now})
+	if len(job.overrides) > maxOverrideRecords {
+		job.overrides = job.overrides[len(job.overrides)-maxOverrideRecords:]
+	}
+	if s.running && job.f != nil && job.state != Running {
+		job.planned = time.Time{}
+		job.retry = time.Time{}
+		job.backoff = 0
+		job.next = job.window.fit(job.nextAfter(now))
+	}
+	job.locker.Unlock()
+	s.locker.Unlock()
+
+	if l := s.logger(LogInfo); l != nil {
+		if by == "" {
+			l.Printf("scheduled: override job %s with %s until %s\n", name, scheduler.Title(), until.String())
+		} else {
+			l.Printf("scheduled: %s override job %s with %s until %s\n", by, name, scheduler.Title(), until.String())
+		}
+	}
+
+	s.reschedule()
+	return nil
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers/ticker"
+)
+
+func TestJob_nextAfter(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	hourly, err := ticker.NewAnchored(time.Hour, start, false)
+	a.NotError(err).NotNil(hourly)
+	minutely, err := ticker.New(20*time.Minute, false)
+	a.NotError(err).NotNil(minutely)
+
+	j := &Job{Scheduler: hourly}
+	a.Equal(j.nextAfter(start), start.Add(time.Hour))
+
+	j.override = &override{scheduler: minutely, until: start.Add(time.Hour)}
+	a.Equal(j.nextAfter(start), start.Add(20*time.Minute))
+	a.Equal(j.nextAfter(start.Add(20*time.Minute)), start.Add(40*time.Minute))
+
+	// 临时调度器的下一次时间达到截止时间，恢复原来的调度器。
+	a.Equal(j.nextAfter(start.Add(40*time.Minute)), start.Add(time.Hour))
+	a.Equal(j.nextAfter(start.Add(time.Hour)), start.Add(2*time.Hour))
+}
+
+func TestServer_OverrideSchedule(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, errlog, nil)
+
+	var count int64
+	a.NotError(srv.Tick("tick", func(time.Time) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	}, time.Hour, false, false))
+
+	s, err := ticker.New(time.Second, false)
+	a.NotError(err).NotNil(s)
+
+	a.Equal(srv.OverrideSchedule("not-exists", s, time.Now().Add(time.Minute)), ErrJobNotFound)
+	a.Equal(srv.OverrideSchedule("tick", nil, time.Now().Add(time.Minute)), ErrNilScheduler)
+	a.Error(srv.OverrideSchedule("tick", s, time.Now().Add(-time.Minute)))
+
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	until := time.Now().Add(2500 * time.Millisecond)
+	a.NotError(srv.OverrideSchedule("tick", s, until))
+	j := srv.jobs[0]
+	o, u := j.Override()
+	a.Equal(o, s).Equal(u, until)
+
+	time.Sleep(3 * time.Second)
+	srv.Stop()
+	<-exit
+
+	a.Equal(atomic.LoadInt64(&count), 2)
+	o, u = j.Override()
+	a.Nil(o).True(u.IsZero())
+	a.True(j.Next().After(until))
+}
+
+func TestServer_OverrideScheduleBy(t *testing.T) {
+	a := assert.New(t)
+	buf := new(bytes.Buffer)
+	srv := NewServer(nil, nil, log.New(buf, "", 0))
+
+	a.NotError(srv.Tick("tick", succFunc, time.Hour, false, false))
+	j := srv.jobs[0]
+	a.Empty(j.OverrideRecords())
+
+	s1, err := ticker.New(time.Second, false)
+	a.NotError(err).NotNil(s1)
+	s2, err := ticker.New(time.Minute, false)
+	a.NotError(err).NotNil(s2)
+
+	before := time.Now()
+	until1 := before.Add(time.Hour)
+	until2 := before.Add(2 * time.Hour)
+	a.NotError(srv.OverrideScheduleBy("ops", "tick", s1, until1))
+	a.NotError(srv.OverrideSchedule("tick", s2, until2))
+	a.Equal(srv.OverrideScheduleBy("ops", "not-exists", s1, until1), ErrJobNotFound)
+
+	records := j.OverrideRecords()
+	a.Equal(len(records), 2)
+	a.Equal(records[0].By, "ops").
+		Equal(records[0].Scheduler, s1).
+		Equal(records[0].Until, until1).
+		False(records[0].At.Before(before))
+	a.Equal(records[1].By, "").
+		Equal(records[1].Scheduler, s2).
+		Equal(records[1].Until, until2).
+		False(records[1].At.Before(records[0].At))
+
+	// 返回的是副本
+	records[0].By = "changed"
+	a.Equal(j.OverrideRecords()[0].By, "ops")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	a.Equal(len(lines), 2)
+	a.True(strings.HasPrefix(lines[0], "scheduled: ops override job tick with "+s1.Title()))
+	a.True(strings.HasPrefix(lines[1], "scheduled: override job tick with "+s2.Title()))
+
+	// 超出数量时丢弃最早的记录
+	for i := 0; i < maxOverrideRecords; i++ {
+		a.NotError(srv.OverrideScheduleBy("loop", "tick", s1, until1))
+	}
+	records = j.OverrideRecords()
+	a.Equal(len(records), maxOverrideRecords)
+	a.Equal(records[0].By, "loop")
+}
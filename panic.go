@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import "fmt"
+
+// PanicError 任务 panic 时记录的错误信息
+//
+// 任务 panic 之后，Job.Err 返回的即为该类型，
+// 以 PanicPropagate 重新抛出时，panic 的值也是该类型。
+type PanicError struct {
+	Job   string      // 任务的名称
+	Value interface{} // 传递给 panic 的原始值
+	Stack []byte      // panic 时的调用栈
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("job %s panic: %v", err.Job, err.Value)
+}
+
+// Unwrap 在 Value 为 error 类型时返回 Value
+func (err *PanicError) Unwrap() error {
+	if e, ok := err.Value.(error); ok {
+		return e
+	}
+	return nil
+}
+
+// SetPanicHandler 设置任务 panic 之后的回调函数
+//
+// 在任务的状态记录完成之后，以及按 PanicPropagate 重新抛出之前调用，
+// 可用于上报监控系统，或是在测试中检测是否有任务 panic，比如 scheduledtest.AssertNoPanic。
+// f 在任务所在的 goroutine 中执行，为 nil 表示不作处理，也是默认值。
+func (s *Server) SetPanicHandler(f func(*PanicError)) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.onPanic = f
+}
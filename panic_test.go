@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/scheduled/schedulers/ticker"
+)
+
+func TestPanicError(t *testing.T) {
+	a := assert.New(t)
+	errPanic := errors.New("panic")
+
+	err := &PanicError{Job: "job", Value: errPanic}
+	a.Equal(err.Error(), "job job panic: panic").
+		True(errors.Is(err, errPanic))
+
+	err = &PanicError{Job: "job", Value: 5}
+	a.Equal(err.Error(), "job job panic: 5").
+		Nil(err.Unwrap())
+}
+
+func TestJob_run_panicError(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	s, err := ticker.New(time.Second, false)
+	a.NotError(err).NotNil(s)
+	j := &Job{name: "fail", f: failFunc, Scheduler: s, at: now}
+	j.init(now)
+
+	var handled *PanicError
+	j.run(PanicRecover, nil, func(err *PanicError) { handled = err }, nil, nil)
+	var perr *PanicError
+	a.True(errors.As(j.Err(), &perr)).
+		Equal(perr, handled).
+		Equal(perr.Job, "fail").
+		Equal(perr.Value, "fail").
+		True(strings.Contains(string(perr.Stack), "panic"))
+
+	// PanicPropagate 抛出的值为 *PanicError
+	handled = nil
+	func() {
+		defer func() {
+			msg := recover()
+			perr, ok := msg.(*PanicError)
+			a.True(ok).Equal(perr, handled).Equal(perr.Value, "fail")
+		}()
+		j.run(PanicPropagate, nil, func(err *PanicError) { handled = err }, nil, nil)
+	}()
+	a.Equal(j.State(), Failed)
+}
+
+func TestServer_SetPanicHandler(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+	a.NotError(srv.Tick("fail", failFunc, time.Second, true, false))
+
+	var count int64
+	srv.SetPanicHandler(func(err *PanicError) {
+		a.Equal(err.Job, "fail")
+		atomic.AddInt64(&count, 1)
+	})
+
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+	time.Sleep(1500 * time.Millisecond)
+	srv.Stop()
+	<-exit
+
+	a.True(atomic.LoadInt64(&count) > 0)
+}
@@ -5,10 +5,12 @@
 // 通过 scheduled 可以实现管理类似 linux 中 crontab 功能的计划任务功能。
 // 当然功能并不止于此，用户可以实现自己的调度算法，定制任务的启动机制。
 //
-// 目前 scheduled 内置了以下三种算法：
+// 目前 scheduled 内置了以下五种算法：
 //  cron 实现了 crontab 中的大部分语法功能；
 //  at 在固定的时间点执行一次任务；
-//  ticker 以固定的时间段执行任务，与 time.Ticker 相同。
+//  calendar 在月末、季末或是财年末等日历周期的最后一天执行任务；
+//  ticker 以固定的时间段执行任务，与 time.Ticker 相同；
+//  adaptive 根据任务的执行结果动态调整时间间隔。
 package scheduled
 
 import "errors"
@@ -17,4 +19,15 @@ import "errors"
 var (
 	ErrNoJobs  = errors.New("任务列表为空")
 	ErrRunning = errors.New("任务已经在运行")
+
+	ErrNilJobFunc   = errors.New("参数 f 不能为空")
+	ErrNilScheduler = errors.New("参数 scheduler 不能为空")
+	ErrJobNotFound  = errors.New("任务不存在")
+
+	// ErrNotReady 表示任务所需的条件还未就绪
+	//
+	// JobFunc 返回该错误时，不会被当作执行失败，而是以指数退避的方式提前重新执行，
+	// 直到退避时间超过正常的下一次执行时间为止。
+	// 重试时传递给 JobFunc 的依然是原本的计划时间，而不是重试的时间。
+	ErrNotReady = errors.New("任务未就绪")
 )
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+
+// Package scheduledtest 提供测试调度算法的辅助函数
+//
+// 方便应用在单元测试中检测其调度配置是否符合预期：
+//  s, err := cron.Parse("0 0 3 * * 1-5")
+//  scheduledtest.AssertFires(t, s, start, []time.Time{...})
+package scheduledtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/issue9/scheduled"
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// AssertFires 断言 s 从 start 开始依次返回 want 中的时间
+//
+// 第一次以 start 作为参数调用 s.Next，之后都以上一次的返回值作为参数，
+// 比较时采用 time.Time.Equal，即不比较时区。
+// 返回值表示断言是否成功。
+func AssertFires(t testing.TB, s schedulers.Scheduler, start time.Time, want []time.Time) bool {
+	t.Helper()
+
+	last := start
+	for i, w := range want {
+		next := s.Next(last)
+		if !next.Equal(w) {
+			t.Errorf("%s 第 %d 次执行时间不正确，返回值：%s，期望值：%s", s.Title(), i+1, next, w)
+			return false
+		}
+		last = next
+	}
+
+	return true
+}
+
+// AssertNeverFiresBetween 断言 s 在 (start, end) 之间不会执行
+//
+// 返回值表示断言是否成功。
+func AssertNeverFiresBetween(t testing.TB, s schedulers.Scheduler, start, end time.Time) bool {
+	t.Helper()
+
+	next := s.Next(start)
+	if !next.IsZero() && next.Before(end) {
+		t.Errorf("%s 在 %s 执行，不应该在 (%s, %s) 之间执行", s.Title(), next, start, end)
+		return false
+	}
+
+	return true
+}
+
+// AssertNoPanic 断言 srv 在运行 d 时长的过程中没有任务 panic
+//
+// 会调用 srv.Serve 运行 d 时长之后再调用 srv.Stop，所有任务都结束时会提前返回；
+// 期间通过 Server.SetPanicHandler 记录 panic 的任务，返回之前恢复为 nil。
+// 每个 panic 都会以 t.Errorf 输出其错误信息和调用栈，Serve 返回的错误也是如此。
+// 返回值表示断言是否成功。
+func AssertNoPanic(t testing.TB, srv *scheduled.Server, d time.Duration) bool {
+	t.Helper()
+
+	var locker sync.Mutex
+	var panics []*scheduled.PanicError
+	srv.SetPanicHandler(func(err *scheduled.PanicError) {
+		locker.Lock()
+		defer locker.Unlock()
+		panics = append(panics, err)
+	})
+	defer srv.SetPanicHandler(nil)
+
+	exit := make(chan error, 1)
+	go func() {
+		exit <- srv.Serve()
+	}()
+
+	var err error
+	select {
+	case err = <-exit:
+	case <-time.After(d):
+		srv.Stop()
+		err = <-exit
+	}
+
+	ok := true
+	if err != nil {
+		t.Errorf("Serve 返回错误：%s", err)
+		ok = false
+	}
+
+	locker.Lock()
+	defer locker.Unlock()
+	for _, p := range panics {
+		t.Errorf("%s\n%s", p, p.Stack)
+		ok = false
+	}
+	return ok
+}
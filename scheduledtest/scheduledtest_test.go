@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+
+package scheduledtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled"
+	"github.com/issue9/scheduled/schedulers/at"
+	"github.com/issue9/scheduled/schedulers/cron"
+)
+
+// 记录错误信息但不使测试失败的 testing.TB
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFires(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := cron.Parse("0 30 9 * * 1-5")
+	a.NotError(err).NotNil(s)
+	a.True(AssertFires(t, s, start, []time.Time{
+		time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 9, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 9, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 9, 30, 0, 0, time.UTC), // 跳过周末
+	}))
+
+	r := &recorder{TB: t}
+	a.False(AssertFires(r, s, start, []time.Time{
+		time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 4, 9, 30, 0, 0, time.UTC),
+	}))
+	a.Equal(len(r.errs), 1)
+}
+
+func TestAssertNeverFiresBetween(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC) // 周六
+
+	s, err := cron.Parse("0 30 9 * * 1-5")
+	a.NotError(err).NotNil(s)
+	a.True(AssertNeverFiresBetween(t, s, start, start.Add(48*time.Hour)))
+
+	r := &recorder{TB: t}
+	a.False(AssertNeverFiresBetween(r, s, start, start.Add(72*time.Hour)))
+	a.Equal(len(r.errs), 1)
+
+	// 零值表示不再执行
+	s = at.At(start)
+	s.Next(start)
+	a.True(AssertNeverFiresBetween(t, s, start, start.Add(time.Hour)))
+}
+
+func TestAssertNoPanic(t *testing.T) {
+	a := assert.New(t)
+
+	srv := scheduled.NewServer(time.UTC, nil, nil)
+	a.NotError(srv.Cron("reboot", func(time.Time) error { return nil }, "@reboot", false))
+	a.True(AssertNoPanic(t, srv, 3*time.Second))
+
+	srv = scheduled.NewServer(time.UTC, nil, nil)
+	a.NotError(srv.Cron("panic", func(time.Time) error { panic("panic") }, "@reboot", false))
+	r := &recorder{TB: t}
+	a.False(AssertNoPanic(r, srv, 3*time.Second))
+	a.Equal(len(r.errs), 1)
+
+	// 超时之后停止服务
+	srv = scheduled.NewServer(time.UTC, nil, nil)
+	a.NotError(srv.Tick("tick", func(time.Time) error { return nil }, time.Hour, false, false))
+	a.True(AssertNoPanic(t, srv, 100*time.Millisecond))
+
+	// Serve 出错
+	r = &recorder{TB: t}
+	a.False(AssertNoPanic(r, scheduled.NewServer(time.UTC, nil, nil), time.Second))
+	a.Equal(len(r.errs), 1)
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+
+// Package adaptive 根据任务的执行结果调整时间间隔的定时器
+//
+// 适用于轮询类的任务：上游没有新数据时逐渐放慢，数据持续到达时加快轮询。
+package adaptive
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Result 任务的执行结果，具体内容由任务与 Adapter 约定。
+type Result interface{}
+
+// Adapter 根据上一次的执行结果 last 和当前的时间间隔 current 计算新的时间间隔
+//
+// 返回值小于 1 秒时，会被当作 1 秒处理。
+type Adapter func(last Result, current time.Duration) time.Duration
+
+// Scheduler 自适应的定时器
+type Scheduler struct {
+	locker sync.Mutex
+
+	interval time.Duration
+	adapt    Adapter
+	result   Result
+	reported bool // 是否有未处理的执行结果
+}
+
+// New 声明自适应的定时器
+//
+// initial 为初始的时间间隔，不能小于 1 秒；
+// 每次通过 Report 提交执行结果之后，下一次调用 Next 时会通过 adapt 重新计算时间间隔。
+func New(initial time.Duration, adapt Adapter) (*Scheduler, error) {
+	if initial < time.Second {
+		return nil, errors.New("参数 initial 的值必须在 1 秒以上")
+	}
+
+	if adapt == nil {
+		return nil, errors.New("参数 adapt 不能为空")
+	}
+
+	return &Scheduler{
+		interval: initial,
+		adapt:    adapt,
+	}, nil
+}
+
+// Report 提交任务的执行结果
+//
+// 一般在任务函数返回之前调用。多次调用时，仅最后一次的结果有效。
+func (s *Scheduler) Report(r Result) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	s.result = r
+	s.reported = true
+}
+
+// Interval 当前的时间间隔
+func (s *Scheduler) Interval() time.Duration {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.interval
+}
+
+// Next 实现 schedulers.Scheduler 接口
+func (s *Scheduler) Next(last time.Time) time.Time {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.reported {
+		s.interval = s.adapt(s.result, s.interval)
+		if s.interval < time.Second {
+			s.interval = time.Second
+		}
+		s.result = nil
+		s.reported = false
+	}
+
+	return last.Add(s.interval)
+}
+
+// Stateful 实现 schedulers.Stateful 接口
+//
+// Next 会消耗由 Report 提交的执行结果，所以总是返回 true。
+func (s *Scheduler) Stateful() bool { return true }
+
+// Title 实现 schedulers.Scheduler 接口
+func (s *Scheduler) Title() string {
+	return fmt.Sprintf("自适应，当前每隔 %s", s.Interval())
+}
+
+func (s *Scheduler) String() string {
+	return s.Title()
+}
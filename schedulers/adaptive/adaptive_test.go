@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+
+package adaptive
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var (
+	_ schedulers.Scheduler = &Scheduler{}
+	_ schedulers.Stateful  = &Scheduler{}
+	_ fmt.Stringer         = &Scheduler{}
+)
+
+// 没有新数据时间隔翻倍，有数据时恢复为 1 秒。
+func backoff(last Result, current time.Duration) time.Duration {
+	if last.(bool) {
+		return time.Second
+	}
+	return current * 2
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := New(time.Millisecond, backoff)
+	a.Error(err).Nil(s)
+
+	s, err = New(time.Second, nil)
+	a.Error(err).Nil(s)
+
+	s, err = New(time.Second, backoff)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "自适应，当前每隔 1s")
+	a.True(schedulers.IsStateful(s))
+}
+
+func TestScheduler_Next(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := New(time.Second, backoff)
+	a.NotError(err).NotNil(s)
+
+	// 未提交结果，保持不变。
+	a.Equal(s.Next(now), now.Add(time.Second))
+	a.Equal(s.Next(now), now.Add(time.Second))
+
+	s.Report(false)
+	a.Equal(s.Next(now), now.Add(2*time.Second))
+	s.Report(false)
+	a.Equal(s.Next(now), now.Add(4*time.Second))
+	a.Equal(s.Interval(), 4*time.Second)
+	a.Equal(s.Next(now), now.Add(4*time.Second)) // 结果仅使用一次
+
+	s.Report(false)
+	s.Report(true) // 仅最后一次有效
+	a.Equal(s.Next(now), now.Add(time.Second))
+
+	// 小于 1 秒的值
+	s, err = New(time.Second, func(Result, time.Duration) time.Duration { return 0 })
+	a.NotError(err).NotNil(s)
+	s.Report(nil)
+	a.Equal(s.Next(now), now.Add(time.Second))
+}
@@ -43,6 +43,17 @@ func (s *scheduler) Title() string {
 	return s.title
 }
 
+func (s *scheduler) String() string {
+	return s.title
+}
+
+// Stateful 实现 schedulers.Stateful 接口
+//
+// 第一次调用 Next 之后，该时间即被使用，所以在此之前调用 Next 会改变内部状态。
+func (s *scheduler) Stateful() bool {
+	return !s.used
+}
+
 func (s *scheduler) Next(last time.Time) time.Time {
 	if s.used {
 		return zero
@@ -3,6 +3,7 @@
 package at
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,7 +12,11 @@ import (
 	"github.com/issue9/scheduled/schedulers"
 )
 
-var _ schedulers.Scheduler = &scheduler{}
+var (
+	_ schedulers.Scheduler = &scheduler{}
+	_ schedulers.Stateful  = &scheduler{}
+	_ fmt.Stringer         = &scheduler{}
+)
 
 func TestAt(t *testing.T) {
 	a := assert.New(t)
@@ -33,3 +38,17 @@ func TestAt(t *testing.T) {
 	next := s.Next(time.Now().In(loc)) // 变成 8 时区，小于零时区的 loc
 	a.True(next.Before(ttt))
 }
+
+func TestAt_Stateful(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := At(now.Add(time.Hour))
+
+	// 预览不会使用掉该时间
+	a.True(schedulers.IsStateful(s)).
+		Nil(schedulers.NextN(s, now, 3))
+	a.Equal(s.Next(now), now.Add(time.Hour))
+
+	a.False(schedulers.IsStateful(s)).
+		Empty(schedulers.NextN(s, now, 3))
+}
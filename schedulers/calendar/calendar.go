@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+
+// Package calendar 提供以日历周期结束时间为准的定时器
+//
+// 比如每月最后一天、每季度最后一个工作日以及财年的最后一天等，
+// 这些时间点无法通过普通的 cron 表达式表示。
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+type scheduler struct {
+	title string
+
+	// months 表示一个周期的月数，offset 表示周期起始月份相对于 1 月的偏移量。
+	months, offset int
+
+	// 是否以最后一个工作日为准，工作日指周一至周五。
+	business bool
+
+	hour, minute, second int
+}
+
+// MonthEnd 返回在每月最后一天的指定时间执行的调度器
+//
+// business 表示是否以每月最后一个工作日（周一至周五）为准。
+func MonthEnd(business bool, hour, minute, second int) (schedulers.Scheduler, error) {
+	return newScheduler("月", 1, 0, business, hour, minute, second)
+}
+
+// QuarterEnd 返回在每季度最后一天的指定时间执行的调度器
+//
+// 季度以 1、4、7、10 月为起始月份，business 的含义与 MonthEnd 相同。
+func QuarterEnd(business bool, hour, minute, second int) (schedulers.Scheduler, error) {
+	return newScheduler("季度", 3, 0, business, hour, minute, second)
+}
+
+// FiscalYearEnd 返回在每个财年最后一天的指定时间执行的调度器
+//
+// start 表示财年的起始月份，比如 time.April 表示财年从 4 月开始，
+// 在 3 月的最后一天结束。business 的含义与 MonthEnd 相同。
+func FiscalYearEnd(start time.Month, business bool, hour, minute, second int) (schedulers.Scheduler, error) {
+	if start < time.January || start > time.December {
+		return nil, fmt.Errorf("无效的起始月份 %d", start)
+	}
+	return newScheduler("财年", 12, int(start)-1, business, hour, minute, second)
+}
+
+func newScheduler(name string, months, offset int, business bool, hour, minute, second int) (schedulers.Scheduler, error) {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 || second < 0 || second > 59 {
+		return nil, errors.New("无效的时间")
+	}
+
+	day := "最后一天"
+	if business {
+		day = "最后一个工作日"
+	}
+
+	title := fmt.Sprintf("每%s%s %02d:%02d:%02d", name, day, hour, minute, second)
+	if offset > 0 {
+		title = fmt.Sprintf("%s（%d 月起）", title, offset+1)
+	}
+
+	return &scheduler{
+		title:    title,
+		months:   months,
+		offset:   offset,
+		business: business,
+		hour:     hour,
+		minute:   minute,
+		second:   second,
+	}, nil
+}
+
+func (s *scheduler) Title() string {
+	return s.title
+}
+
+func (s *scheduler) String() string {
+	return s.title
+}
+
+func (s *scheduler) Next(last time.Time) time.Time {
+	// 以公元元年 1 月为 0 的月份序号
+	index := last.Year()*12 + int(last.Month()) - 1
+
+	for {
+		if mod(index+1-s.offset, s.months) == 0 { // 当前月份为周期的最后一个月
+			t := s.date(index/12, time.Month(index%12+1), last.Location())
+			if t.After(last) {
+				return t
+			}
+		}
+		index++
+	}
+}
+
+// 获取 year-month 中符合要求的最后一天
+func (s *scheduler) date(year int, month time.Month, loc *time.Location) time.Time {
+	t := time.Date(year, month+1, 0, s.hour, s.minute, s.second, 0, loc)
+
+	if s.business {
+		switch t.Weekday() {
+		case time.Saturday:
+			t = t.AddDate(0, 0, -1)
+		case time.Sunday:
+			t = t.AddDate(0, 0, -2)
+		}
+	}
+
+	return t
+}
+
+func mod(x, m int) int {
+	x %= m
+	if x < 0 {
+		x += m
+	}
+	return x
+}
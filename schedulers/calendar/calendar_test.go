@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+
+package calendar
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var (
+	_ schedulers.Scheduler = &scheduler{}
+	_ fmt.Stringer         = &scheduler{}
+)
+
+func TestMonthEnd(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := MonthEnd(false, 24, 0, 0)
+	a.Error(err).Nil(s)
+
+	s, err = MonthEnd(false, 9, 30, 0)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "每月最后一天 09:30:00")
+
+	last := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	next := s.Next(last)
+	a.Equal(next, time.Date(2020, 1, 31, 9, 30, 0, 0, time.UTC))
+
+	next = s.Next(next)
+	a.Equal(next, time.Date(2020, 2, 29, 9, 30, 0, 0, time.UTC)) // 闰年
+
+	next = s.Next(time.Date(2020, 12, 31, 10, 0, 0, 0, time.UTC)) // 跨年
+	a.Equal(next, time.Date(2021, 1, 31, 9, 30, 0, 0, time.UTC))
+
+	// 继承时区
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	next = s.Next(time.Date(2021, 2, 1, 0, 0, 0, 0, loc))
+	a.Equal(next, time.Date(2021, 2, 28, 9, 30, 0, 0, loc))
+
+	// business
+	s, err = MonthEnd(true, 0, 0, 0)
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)) // 2020-05-31 为周日
+	a.Equal(next, time.Date(2020, 5, 29, 0, 0, 0, 0, time.UTC))
+}
+
+func TestQuarterEnd(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := QuarterEnd(true, 18, 0, 0)
+	a.NotError(err).NotNil(s)
+
+	next := s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2020, 3, 31, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(next)
+	a.Equal(next, time.Date(2020, 6, 30, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)) // 2020-12-31 为周四
+	a.Equal(next, time.Date(2020, 12, 31, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)) // 2021-03-31 为周三
+	a.Equal(next, time.Date(2021, 3, 31, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)) // 2021-06-30 为周三
+	a.Equal(next, time.Date(2021, 6, 30, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)) // 2022-09-30 为周五
+	a.Equal(next, time.Date(2022, 9, 30, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)) // 2023-06-30 为周五
+	a.Equal(next, time.Date(2023, 6, 30, 18, 0, 0, 0, time.UTC))
+
+	next = s.Next(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)) // 2023-09-30 为周六
+	a.Equal(next, time.Date(2023, 9, 29, 18, 0, 0, 0, time.UTC))
+}
+
+func TestFiscalYearEnd(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := FiscalYearEnd(13, false, 0, 0, 0)
+	a.Error(err).Nil(s)
+
+	s, err = FiscalYearEnd(time.April, false, 0, 0, 0)
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "每财年最后一天 00:00:00（4 月起）")
+
+	next := s.Next(time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	next = s.Next(next)
+	a.Equal(next, time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	s, err = FiscalYearEnd(time.January, false, 0, 0, 0)
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Builder 以代码的形式构建 cron 表达式
+//
+//  s, err := cron.Build().Seconds(0).Minutes(30).Hours(9, 18).
+//      Weekdays(time.Monday, time.Friday).Scheduler()
+// 等同于 Parse("0 30 9,18 * * 1,5")。
+type Builder struct {
+	fields [indexSize + 1]string
+	opts   []Option
+}
+
+// Build 声明 Builder
+//
+// 秒字段的默认值为 0，其它字段的默认值均为 *，年份字段默认不指定。
+// 比如 Build().Minutes(5) 等同于 Parse("0 5 * * * *")，即每小时的 05:00 执行一次。
+func Build() *Builder {
+	b := &Builder{}
+	for i := 0; i < indexSize; i++ {
+		b.fields[i] = "*"
+	}
+	b.fields[secondIndex] = "0"
+	return b
+}
+
+// Seconds 指定秒字段的值，不指定任何值表示 *，以下方法与此相同。
+func (b *Builder) Seconds(v ...int) *Builder { return b.set(secondIndex, v) }
+
+// Minutes 指定分字段的值
+func (b *Builder) Minutes(v ...int) *Builder { return b.set(minuteIndex, v) }
+
+// Hours 指定小时字段的值
+func (b *Builder) Hours(v ...int) *Builder { return b.set(hourIndex, v) }
+
+// Days 指定日字段的值
+func (b *Builder) Days(v ...int) *Builder { return b.set(dayIndex, v) }
+
+// Months 指定月字段的值
+func (b *Builder) Months(v ...time.Month) *Builder {
+	vals := make([]int, 0, len(v))
+	for _, m := range v {
+		vals = append(vals, int(m))
+	}
+	return b.set(monthIndex, vals)
+}
+
+// Weekdays 指定星期字段的值
+func (b *Builder) Weekdays(v ...time.Weekday) *Builder {
+	vals := make([]int, 0, len(v))
+	for _, w := range v {
+		vals = append(vals, int(w))
+	}
+	return b.set(weekIndex, vals)
+}
+
+// Years 指定年份字段的值
+func (b *Builder) Years(v ...int) *Builder {
+	if len(v) == 0 {
+		b.fields[yearIndex] = ""
+		return b
+	}
+	return b.set(yearIndex, v)
+}
+
+// Options 指定传递给 Parse 的扩展选项
+func (b *Builder) Options(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+func (b *Builder) set(index int, v []int) *Builder {
+	if len(v) == 0 {
+		b.fields[index] = "*"
+		return b
+	}
+
+	vals := make([]int, 0, len(v))
+	vals = append(vals, v...)
+	sort.Ints(vals)
+
+	uniq := vals[:1]
+	for _, val := range vals[1:] {
+		if val != uniq[len(uniq)-1] {
+			uniq = append(uniq, val)
+		}
+	}
+	b.fields[index] = formatValues(uniq)
+	return b
+}
+
+// String 返回构建的表达式
+//
+// 返回值不检测各个值是否在有效范围内，可由 Scheduler 检测。
+func (b *Builder) String() string {
+	if b.fields[yearIndex] == "" {
+		return strings.Join(b.fields[:indexSize], " ")
+	}
+	return strings.Join(b.fields[:], " ")
+}
+
+// Scheduler 根据构建的表达式生成 schedulers.Scheduler
+//
+// 超出范围的值会返回 *FieldError 类型的错误，与 Parse 相同。
+func (b *Builder) Scheduler() (schedulers.Scheduler, error) {
+	return Parse(b.String(), b.opts...)
+}
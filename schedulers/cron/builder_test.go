@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+var _ fmt.Stringer = &Builder{}
+
+func TestBuilder(t *testing.T) {
+	a := assert.New(t)
+
+	b := Build()
+	a.Equal(b.String(), "0 * * * * *")
+
+	// 秒字段默认为 0
+	s, err := Build().Minutes(5).Scheduler()
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(time.Date(2020, 1, 1, 0, 10, 30, 0, time.UTC)), time.Date(2020, 1, 1, 1, 5, 0, 0, time.UTC))
+
+	b.Seconds(0).Minutes(30).Hours(18, 9).Weekdays(time.Monday, time.Friday)
+	a.Equal(b.String(), "0 30 9,18 * * 1,5")
+	s, err = b.Scheduler()
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(time.Date(2020, 1, 3, 10, 0, 0, 0, time.UTC)), time.Date(2020, 1, 3, 18, 30, 0, 0, time.UTC))
+
+	// 连续的值以范围表示，重复的值被忽略
+	b = Build().Seconds(0).Minutes(0).Hours(9, 10, 11, 12, 11).Days(1, 15).Months(time.March, time.January).Years(2030)
+	a.Equal(b.String(), "0 0 9-12 1,15 1,3 * 2030")
+
+	// 清空
+	b.Days().Years()
+	a.Equal(b.String(), "0 0 9-12 * 1,3 *")
+
+	// 选项
+	b = Build().Seconds(0).Minutes(0).Hours(0).Days(1).Weekdays(time.Monday).Options(DayAndWeek())
+	s, err = b.Scheduler()
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	// 超出范围
+	s, err = Build().Hours(24).Scheduler()
+	a.Error(err).Nil(s)
+	var ferr *FieldError
+	a.True(errors.As(err, &ferr)).Equal(ferr.Index, hourIndex)
+	a.True(errors.Is(err, ErrOutOfRange))
+}
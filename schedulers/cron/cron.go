@@ -5,11 +5,17 @@ package cron
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/issue9/scheduled/schedulers"
-	"github.com/issue9/scheduled/schedulers/at"
+	"github.com/issue9/scheduled/schedulers/calendar"
+	"github.com/issue9/scheduled/schedulers/ticker"
 )
 
 // 表示 cron.data 中各个元素的索引值
@@ -21,8 +27,53 @@ const (
 	monthIndex
 	weekIndex
 	indexSize
+
+	// 可选的年份字段，不保存在 cron.data 中。
+	yearIndex = indexSize
 )
 
+// 各个字段的名称，与 cron.data 的索引值对应。
+var fieldNames = []string{"second", "minute", "hour", "day", "month", "week", "year"}
+
+// FieldError 表示表达式中某个字段的错误
+//
+// 可以根据其中的内容生成本地化的错误提示，而不必解析错误信息。
+type FieldError struct {
+	Index    int    // 字段在表达式中的索引，从 0 开始。
+	Field    string // 字段名称，可以是 second、minute、hour、day、month、week 或是 year。
+	Input    string // 字段的原始内容
+	Min, Max int    // 出错部分的取值范围，比如 L-n 中 n 的范围为 [1,30]。
+	Err      error  // 具体的错误信息
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("字段 %s 的值 %s 无效：%s", e.Field, e.Input, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func newFieldError(index int, input string, err error) *FieldError {
+	b := bounds[index]
+	var berr *boundError
+	if errors.As(err, &berr) {
+		b, err = berr.bound, berr.err
+	}
+
+	return &FieldError{
+		Index: index,
+		Field: fieldNames[index],
+		Input: input,
+		Min:   b.min,
+		Max:   b.max,
+		Err:   err,
+	}
+}
+
+// ErrImpossible 表示表达式中日与月份的组合永远不会存在，比如 2 月 30 日。
+var ErrImpossible = errors.New("表达式永远不会执行")
+
 // 常用的便捷指令
 var direct = map[string]string{
 	"@yearly":   "0 0 0 1 1 *",
@@ -34,13 +85,225 @@ var direct = map[string]string{
 	"@hourly":   "0 0 * * * *",
 }
 
+// 保护 direct，RegisterDirective 可能与 Parse 同时调用。
+var directLocker sync.RWMutex
+
+// 不在 direct 中的内置指令，值为其参数数量的最小值和最大值。
+var reservedDirectives = map[string][2]int{
+	"@reboot":          {0, 0},
+	"@every":           {1, 1},
+	"@month-end":       {0, 0},
+	"@quarter-end":     {0, 0},
+	"@fiscal-year-end": {0, 1},
+}
+
+// DirectiveArgs 返回指令 name 可以接受的参数数量
+//
+// 内置的以及由 RegisterDirective 注册的指令，found 为 true，
+// 比如 @every 返回 1 和 1，@fiscal-year-end 返回 0 和 1，@daily 返回 0 和 0。
+// 可用于从包含指令的文本中分离出指令的参数，比如 crontab 中的任务行。
+func DirectiveArgs(name string) (min, max int, found bool) {
+	if args, found := reservedDirectives[name]; found {
+		return args[0], args[1], true
+	}
+
+	directLocker.RLock()
+	defer directLocker.RUnlock()
+	_, found = direct[name]
+	return 0, 0, found
+}
+
+// RegisterDirective 注册自定义的便捷指令
+//
+// 注册之后，Parse 等函数可以像 @daily 一样使用 name 代替 spec，
+// 比如 RegisterDirective("@business-hours", "0 0 9-17 * * 1-5")。
+// name 必须以 @ 开头且不能包含空白字符，也不能与内置的以及已经注册的指令同名；
+// spec 的格式与 Parse 相同，可以带 CRON_TZ= 前缀或是以分号分隔的多个表达式，但不能以 @ 开头。
+func RegisterDirective(name, spec string) error {
+	if len(name) < 2 || name[0] != '@' || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return errors.New("无效的指令名称：" + name)
+	}
+
+	if _, found := reservedDirectives[name]; found {
+		return errors.New("与内置指令同名：" + name)
+	}
+
+	if spec == "" || spec[0] == '@' {
+		return errors.New("参数 spec 必须是 cron 表达式")
+	}
+	if _, err := Parse(spec); err != nil {
+		return err
+	}
+
+	directLocker.Lock()
+	defer directLocker.Unlock()
+
+	if _, found := direct[name]; found {
+		return errors.New("指令已经存在：" + name)
+	}
+	direct[name] = spec
+	return nil
+}
+
+// 删除由 RegisterDirective 注册的指令
+//
+// 仅用于在测试结束之后恢复全局的状态，调用者需要保证 name 不是内置的指令。
+func unregisterDirective(name string) {
+	directLocker.Lock()
+	defer directLocker.Unlock()
+	delete(direct, name)
+}
+
 type cron struct {
 	// 依次保存着 cron 语法中各个字段解析后的内容。
 	data []fields
 
+	// 扩展的日期字段，由 Option 指定，零值表示不作限制。
+	// weekOfMonth 表示月中的第几周，每月的 1-7 日为第一周，以此类推；
+	// dayOfYear 表示年中的第几天，下标即为天数。
+	weekOfMonth fields
+	dayOfYear   []bool
+
+	// 日字段中 W 相关的值，即离该日最近的工作日，其中 last 表示 LW。
+	nearest fields
+
+	// 日字段中 L-n 相关的值，即每月最后一天之前的第 n 天。
+	beforeLast fields
+
+	// 星期字段中 # 和 L 相关的值，下标为星期，值为该星期在当月中的序号，L 以 last 表示。
+	nth [7]fields
+
+	// 可选的年份字段，下标为与 bounds[yearIndex].min 的差值，nil 表示不作限制；
+	// maxYear 为其中最大的年份。
+	years   []bool
+	maxYear int
+
+	// 是否采用严格模式解析表达式
+	strict bool
+
+	// 日与星期字段同时指定时，是否以与的形式组合，默认为或。
+	dayAndWeek bool
+
+	// 平年中 2 月 29 日的处理方式
+	leapDay LeapDayPolicy
+
+	// 计算 H 的值时所采用的键名
+	hashKey string
+
 	title string
 }
 
+// Option 用于指定 Parse 的扩展选项
+type Option func(*cron) error
+
+// LeapDayPolicy 平年中 2 月 29 日的处理方式
+type LeapDayPolicy int8
+
+// 平年中 2 月 29 日的处理方式
+const (
+	// LeapDaySkip 跳过平年，即只在闰年执行，默认值。
+	LeapDaySkip LeapDayPolicy = iota
+
+	// LeapDayFeb28 在平年的 2 月 28 日执行
+	LeapDayFeb28
+
+	// LeapDayMar1 在平年的 3 月 1 日执行
+	LeapDayMar1
+)
+
+// WeekOfMonth 限定只在每月的指定周执行
+//
+// weeks 的取值范围为 [1,5]，每月的 1-7 日为第一周，8-14 日为第二周，以此类推。
+func WeekOfMonth(weeks ...int) Option {
+	return func(c *cron) error {
+		if len(weeks) == 0 {
+			return errors.New("参数 weeks 不能为空")
+		}
+
+		for _, w := range weeks {
+			if w < 1 || w > 5 {
+				return fmt.Errorf("值 %d %w：[1,5]", w, ErrOutOfRange)
+			}
+			c.weekOfMonth |= 1 << uint64(w)
+		}
+		return nil
+	}
+}
+
+// DayOfYear 限定只在每年的指定天数执行
+//
+// days 的取值范围为 [1,366]，第 366 天仅在闰年存在。
+func DayOfYear(days ...int) Option {
+	return func(c *cron) error {
+		if len(days) == 0 {
+			return errors.New("参数 days 不能为空")
+		}
+
+		c.dayOfYear = make([]bool, 367)
+		for _, d := range days {
+			if d < 1 || d > 366 {
+				return fmt.Errorf("值 %d %w：[1,366]", d, ErrOutOfRange)
+			}
+			c.dayOfYear[d] = true
+		}
+		return nil
+	}
+}
+
+// DayAndWeek 日与星期字段同时指定时，以与的形式组合
+//
+// 默认与传统的 cron 相同，日与星期字段同时指定时，满足其中之一即可，
+// 比如 0 0 0 13 * 5 表示每月 13 日以及每个周五；
+// 指定此选项之后，需要同时满足，即只在 13 日同时也是周五时执行。
+// 任一字段为 * 或 ? 时，两者的效果相同。
+func DayAndWeek() Option {
+	return func(c *cron) error {
+		c.dayAndWeek = true
+		return nil
+	}
+}
+
+// LeapDay 指定平年中 2 月 29 日的处理方式
+//
+// 仅在日字段明确包含 29 且月份字段包含 2 月时有效，比如 0 0 0 29 2 * 和 0 0 0 1,29 * *，
+// 而 0 0 0 * 2 * 这类本就包含 28 日的表达式不受影响。
+func LeapDay(p LeapDayPolicy) Option {
+	return func(c *cron) error {
+		if p < LeapDaySkip || p > LeapDayMar1 {
+			return fmt.Errorf("无效的值 %d", p)
+		}
+		c.leapDay = p
+		return nil
+	}
+}
+
+// Strict 以严格模式解析表达式
+//
+// 默认情况下，解析器会忽略多余的空白字符和逗号，比如 "1,2,4,7," 和以 tab 分隔的字段；
+// 严格模式下，字段之间只能以单个空格分隔，不能包含空值，
+// 数值也不能包含前导零和正号，比如 05 和 +5。适用于需要对配置内容进行严格检测的场景。
+func Strict() Option {
+	return func(c *cron) error {
+		c.strict = true
+		return nil
+	}
+}
+
+// Hash 指定计算 H 的值时所采用的键名
+//
+// 表达式中的 H 会被替换成由 key 计算的固定值，一般为任务的名称，
+// 使用相同表达式的不同任务，可以分散在不同的时间点执行。
+// 表达式中包含 H 时，必须指定此选项。
+func Hash(key string) Option {
+	return func(c *cron) error {
+		if key == "" {
+			return errors.New("参数 key 不能为空")
+		}
+		c.hashKey = key
+		return nil
+	}
+}
+
 // Title 获取标题名称
 func (c *cron) Title() string {
 	return c.title
@@ -49,8 +312,9 @@ func (c *cron) Title() string {
 // Parse 根据 spec 初始化 schedulers.Scheduler
 //
 // spec 的格式如下：
-//  * * * * * *
-//  | | | | | |
+//  * * * * * * [*]
+//  | | | | | |  |
+//  | | | | | |  -- 年，可选
 //  | | | | | --- 星期
 //  | | | | ----- 月
 //  | | | ------- 日
@@ -58,14 +322,32 @@ func (c *cron) Title() string {
 //  | ----------- 分
 //  ------------- 秒
 //
-// 星期与日若同时存在，则以或的形式组合。
+// 星期与日若同时存在，则以或的形式组合，可以通过 DayAndWeek 改为与。
+// 年份的取值范围为 [1970,2099]，超过指定的最大年份之后，Next 返回零值，表示不再执行。
+// 秒数的取值范围为 [0,59]，time 包不处理闰秒，所以也不支持表示闰秒的 60。
+// 2 月 29 日在平年中默认被跳过，可以通过 LeapDay 改变此行为。
 //
 // 支持以下符号：
 //  - 表示范围
 //  , 表示和
+//  / 表示步长，比如 */15、10-50/10 以及 5/15（等同于 5-max/15），
+//    步长必须大于 0 且不能超出字段的取值范围，比如秒数中的 */60 是无效的；
+//    但可以超出剩余的范围，此时只产生起始值，比如分钟中的 50/10 仅表示 50。
+//  L 表示每月的最后一天，仅可用于日字段，比如 0 0 0 L * *，
+//    L-n 表示最后一天之前的第 n 天，比如 L-3；
+//    用于星期字段时表示每月的最后一个星期几，比如 5L 表示每月的最后一个周五。
+//  W 表示离指定日期最近的工作日，仅可用于日字段，比如 15W，
+//    不会跨越月份，LW 表示每月的最后一个工作日。
+//  # 表示每月的第几个星期几，仅可用于星期字段，比如 5#3 表示每月的第三个周五。
+//  H 表示由 Hash 选项指定的键名计算出的固定值，比如 H H * * * * 和 H/15 * * * * *，
+//    日字段中的取值范围为 [1,28]，保证每个月都会执行。
+//  ? 表示不指定值，仅可用于日和星期字段，且不能同时使用，
+//    用于兼容 Quartz 的表达式，比如 0 0 12 ? * MON。
+//
+// 月份和星期可以使用英文名称的缩写，不区分大小写，比如 MON-FRI 和 JAN,JUL。
 //
 // 同时支持以下便捷指令：
-//  @reboot:   启动时执行一次
+//  @reboot:   启动时立即执行一次，之后不再执行，即使再次调用 Server.Serve 也是如此
 //  @yearly:   0 0 0 1 1 *
 //  @annually: 0 0 0 1 1 *
 //  @monthly:  0 0 0 1 * *
@@ -73,22 +355,82 @@ func (c *cron) Title() string {
 //  @daily:    0 0 0 * * *
 //  @midnight: 0 0 0 * * *
 //  @hourly:   0 0 * * * *
-func Parse(spec string) (schedulers.Scheduler, error) {
+//  @every d:  每隔 d 执行一次，d 的格式与 time.ParseDuration 相同，比如 @every 1h30m，
+//             具体可参考 schedulers/ticker。
+//
+// 以及以下日历相关的指令，具体可参考 schedulers/calendar：
+//  @month-end:          每月最后一天的 00:00:00
+//  @quarter-end:        每季度最后一天的 00:00:00
+//  @fiscal-year-end [m]: 每财年最后一天的 00:00:00，m 为财年的起始月份，默认为 1。
+//
+// spec 可以以 CRON_TZ= 或是 TZ= 开头，表示该表达式采用指定的时区计算，
+// 而不是 Next 参数的时区，比如 CRON_TZ=Asia/Shanghai 0 0 9 * * *，
+// 具体可参考 schedulers.InLocation。
+//
+// 多个表达式可以用分号分隔，比如 0 0 3 * * 1-5; 0 0 12 * * 6，
+// 表示其中任意一个表达式的执行时间，每个表达式可以有自己的 CRON_TZ= 前缀，
+// 具体可参考 schedulers.Union。
+//
+// opts 可以指定表达式之外的扩展选项，仅对 cron 表达式有效，
+// @reboot、@every 和日历相关的指令不能指定 opts，但 Hash 除外，会被直接忽略，
+// 方便调用者无需判断表达式的类型，总是传递 Hash。
+func Parse(spec string, opts ...Option) (schedulers.Scheduler, error) {
+	if strings.IndexByte(spec, ';') >= 0 {
+		return parseUnion(spec, opts...)
+	}
+
+	if loc, rest, found, err := parseTZ(spec); found {
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := Parse(rest, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return schedulers.InLocation(s, loc), nil
+	}
+
 	switch {
 	case spec == "":
 		return nil, errors.New("参数 spec 不能为空")
 	case spec == "@reboot":
-		return at.At(time.Time{}), nil
+		if err := checkDirectiveOptions(spec, opts); err != nil {
+			return nil, err
+		}
+		return &reboot{}, nil
+	case strings.HasPrefix(spec, "@every "):
+		if err := checkDirectiveOptions("@every", opts); err != nil {
+			return nil, err
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, err
+		}
+		return ticker.New(d, false)
 	case spec[0] == '@':
+		if s, found, err := parseCalendar(spec); found {
+			if err != nil {
+				return nil, err
+			}
+			if err := checkDirectiveOptions(spec, opts); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+
+		directLocker.RLock()
 		d, found := direct[spec]
+		directLocker.RUnlock()
 		if !found {
 			return nil, errors.New("未找到指令:" + spec)
 		}
-		spec = d
+		return Parse(d, opts...) // d 可能包含 CRON_TZ= 前缀或是多个表达式
 	}
 
 	fs := strings.Fields(spec)
-	if len(fs) != indexSize {
+	if len(fs) != indexSize && len(fs) != indexSize+1 {
 		return nil, errors.New("长度不正确")
 	}
 
@@ -97,11 +439,55 @@ func Parse(spec string) (schedulers.Scheduler, error) {
 		data:  make([]fields, indexSize),
 	}
 
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.strict {
+		if err := checkStrict(spec); err != nil {
+			return nil, err
+		}
+	} else {
+		c.title = strings.Join(fs, " ")
+	}
+
 	allAny := true // 是否所有字段都是 any
+	if len(fs) > indexSize {
+		years, max, err := parseYearField(fs[yearIndex])
+		if err != nil {
+			return nil, newFieldError(yearIndex, fs[yearIndex], err)
+		}
+		c.years, c.maxYear = years, max
+		fs = fs[:indexSize]
+	}
+
+	if fs[dayIndex] == "?" && fs[weekIndex] == "?" {
+		return nil, errors.New("日和星期不能同时为 ?")
+	}
+
 	for i, field := range fs {
-		vals, err := parseField(i, field)
+		if field == "?" && (i == dayIndex || i == weekIndex) {
+			field = "*"
+		}
+
+		field, err := replaceHash(i, field, c.hashKey)
 		if err != nil {
-			return nil, err
+			return nil, newFieldError(i, fs[i], err)
+		}
+
+		var vals fields
+		switch i {
+		case dayIndex:
+			vals, c.nearest, c.beforeLast, err = parseDayField(field)
+		case weekIndex:
+			vals, c.nth, err = parseWeekField(field)
+		default:
+			vals, err = parseField(i, field)
+		}
+		if err != nil {
+			return nil, newFieldError(i, fs[i], err)
 		}
 
 		if allAny && vals != any {
@@ -119,5 +505,200 @@ func Parse(spec string) (schedulers.Scheduler, error) {
 		return nil, errors.New("所有项都为 *")
 	}
 
+	if !c.possible() {
+		return nil, ErrImpossible
+	}
+
 	return c, nil
 }
+
+// 判断日与月份的组合是否可能存在
+//
+// 仅检测日字段与月份字段的组合，比如 2 月 30 日以及 4 月 31 日；
+// 星期字段以或的形式参与时，总是认为可能存在。
+func (c *cron) possible() bool {
+	weeks := c.data[weekIndex]
+	if weekSet := (weeks != any && weeks != step) || c.nth != [7]fields{}; weekSet && !c.dayAndWeek {
+		return true
+	}
+
+	days := c.data[dayIndex]
+	if days == any || days == step || days&last != 0 || c.nearest&last != 0 {
+		return true
+	}
+
+	for m := time.January; m <= time.December; m++ {
+		if !c.data[monthIndex].match(int(m)) {
+			continue
+		}
+
+		monthDays := getMonthDays(m, 2000) // 以闰年计算，2 月有 29 日。
+		for d := 1; d <= monthDays; d++ {
+			if days.match(d) || c.nearest.match(d) || (d < monthDays && c.beforeLast.match(d)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// 检测 opts 是否可用于 @reboot 等不是 cron 表达式的指令
+//
+// 这些指令中不会有 H，所以 Hash 会被忽略，其它选项则返回错误。
+func checkDirectiveOptions(name string, opts []Option) error {
+	c := &cron{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return err
+		}
+	}
+
+	c.hashKey = ""
+	if !reflect.DeepEqual(c, &cron{}) {
+		return errors.New(name + " 不支持扩展选项")
+	}
+	return nil
+}
+
+func parseUnion(spec string, opts ...Option) (schedulers.Scheduler, error) {
+	specs := strings.Split(spec, ";")
+	ss := make([]schedulers.Scheduler, 0, len(specs))
+	for i, item := range specs {
+		s, err := Parse(strings.TrimSpace(item), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个表达式：%w", i+1, err)
+		}
+		ss = append(ss, s)
+	}
+	return schedulers.Union(ss...), nil
+}
+
+// Validate 检测 spec 是否为合法的表达式
+//
+// 字段内容的错误以 *FieldError 的形式返回，可以通过 errors.As 获取出错字段的索引和内容，
+// 再通过 errors.Is 与 ErrOutOfRange 等值比较得到具体的原因。
+// spec 和 opts 的格式与 Parse 相同。
+func Validate(spec string, opts ...Option) error {
+	_, err := Parse(spec, opts...)
+	return err
+}
+
+// Fields 返回 spec 中各个字段所包含的值
+//
+// 返回值依次为秒、分、小时、日、月和星期中所有可能的值，按从小到大排序，
+// 星期中的 7 会被当作 0 处理。* 表示该字段范围内的所有值。
+// 日字段中的 L、W、L-n 以及星期字段中的 #、L 无法以具体的值表示，不会出现在结果中，
+// 同样也不包含年份字段。
+// 可用于在不重新实现解析器的前提下，展示表达式的触发时间。
+//
+// spec 的格式与 Parse 相同，但不能是 @reboot 等非 cron 表达式的指令。
+func Fields(spec string) ([indexSize][]uint8, error) {
+	var ret [indexSize][]uint8
+
+	s, err := Parse(spec)
+	if err != nil {
+		return ret, err
+	}
+
+	c, ok := s.(*cron)
+	if !ok {
+		return ret, errors.New("不是 cron 表达式：" + spec)
+	}
+
+	for i, fs := range c.data {
+		b := bounds[i]
+		if i == weekIndex { // 7 会被转换成 0，不会出现在结果中。
+			b.max = 6
+		}
+		ret[i] = fs.values(b)
+	}
+	return ret, nil
+}
+
+// 严格模式下对 spec 的额外检测
+func checkStrict(spec string) error {
+	if strings.Join(strings.Fields(spec), " ") != spec {
+		return errors.New("严格模式下字段之间只能以单个空格分隔")
+	}
+
+	for _, field := range strings.Fields(spec) {
+		for _, v := range strings.Split(field, ",") {
+			if v == "" {
+				return fmt.Errorf("严格模式下 %s 不能包含空值", field)
+			}
+
+			for _, n := range strings.FieldsFunc(v, func(r rune) bool { return r == '-' || r == '/' }) {
+				if n[0] == '+' || (len(n) > 1 && n[0] == '0') {
+					return fmt.Errorf("严格模式下不能使用有歧义的数值 %s", n)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// 解析 spec 中的 CRON_TZ= 或是 TZ= 前缀
+//
+// found 表示是否存在前缀，rest 为去掉前缀之后的内容。
+func parseTZ(spec string) (loc *time.Location, rest string, found bool, err error) {
+	var name string
+	switch {
+	case strings.HasPrefix(spec, "CRON_TZ="):
+		name = spec[len("CRON_TZ="):]
+	case strings.HasPrefix(spec, "TZ="):
+		name = spec[len("TZ="):]
+	default:
+		return nil, spec, false, nil
+	}
+
+	if index := strings.IndexAny(name, " \t"); index >= 0 {
+		name, rest = name[:index], strings.TrimSpace(name[index:])
+	}
+	if rest == "" {
+		return nil, "", true, errors.New("缺少表达式")
+	}
+
+	loc, err = time.LoadLocation(name)
+	return loc, rest, true, err
+}
+
+// 解析日历相关的指令
+//
+// found 表示 spec 是否为日历相关的指令。
+func parseCalendar(spec string) (s schedulers.Scheduler, found bool, err error) {
+	fs := strings.Fields(spec)
+	name, args := fs[0], fs[1:]
+
+	switch name {
+	case "@month-end", "@quarter-end":
+		if len(args) > 0 {
+			return nil, true, errors.New("长度不正确")
+		}
+
+		if name == "@month-end" {
+			s, err = calendar.MonthEnd(false, 0, 0, 0)
+		} else {
+			s, err = calendar.QuarterEnd(false, 0, 0, 0)
+		}
+	case "@fiscal-year-end":
+		if len(args) > 1 {
+			return nil, true, errors.New("长度不正确")
+		}
+
+		start := time.January
+		if len(args) == 1 {
+			m, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, true, err
+			}
+			start = time.Month(m)
+		}
+		s, err = calendar.FiscalYearEnd(start, false, 0, 0, 0)
+	default:
+		return nil, false, nil
+	}
+
+	return s, true, err
+}
@@ -3,15 +3,23 @@
 package cron
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/issue9/assert"
 
 	"github.com/issue9/scheduled/schedulers"
 )
 
-var _ schedulers.Scheduler = &cron{}
+var (
+	_ schedulers.Scheduler     = &cron{}
+	_ schedulers.PrevScheduler = &cron{}
+	_ fmt.Stringer             = &cron{}
+)
 
 // 2**y1 + 2**y2 + 2**y3 ...
 func pow2(y ...uint64) fields {
@@ -53,6 +61,10 @@ func TestParse(t *testing.T) {
 			expr: "* 3 * * * 6",
 			vals: []fields{any, pow2(3), step, step, step, pow2(6)},
 		},
+		{
+			expr: "*/20 */15 * * * *",
+			vals: []fields{pow2(0, 20, 40), pow2(0, 15, 30, 45), step, step, step, step},
+		},
 		{
 			expr: "@daily",
 			vals: []fields{pow2(0), pow2(0), pow2(0), step, step, step},
@@ -108,3 +120,476 @@ func TestParse(t *testing.T) {
 		a.Equal(c.data, v.vals, "测试 %s 时出错，期望值：%v，实际返回值：%v", v.expr, v.vals, c.data)
 	}
 }
+
+func TestParse_calendar(t *testing.T) {
+	a := assert.New(t)
+	last := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	s, err := Parse("@month-end")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(last), time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	s, err = Parse("@quarter-end")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(last), time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	s, err = Parse("@fiscal-year-end")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(last), time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	s, err = Parse("@fiscal-year-end 4")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(last), time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC))
+
+	s, err = Parse("@fiscal-year-end 13")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@fiscal-year-end x")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@month-end 1")
+	a.Error(err).Nil(s)
+}
+
+func TestParse_impossible(t *testing.T) {
+	a := assert.New(t)
+
+	for _, spec := range []string{
+		"0 0 0 30 2 *",
+		"0 0 0 30,31 2 *",
+		"0 0 0 31 4,6,9,11 *",
+		"0 0 0 30W 2 *",
+		"0 0 0 L-29 2 *",
+	} {
+		s, err := Parse(spec)
+		a.Equal(err, ErrImpossible, spec).Nil(s)
+	}
+
+	for _, spec := range []string{
+		"0 0 0 29 2 *",
+		"0 0 0 31 4,5 *",
+		"0 0 0 L 2 *",
+		"0 0 0 LW 2 *",
+		"0 0 0 L-28 2 *",
+		"0 0 0 30 2 1", // 星期以或的形式组合
+	} {
+		s, err := Parse(spec)
+		a.NotError(err, spec).NotNil(s)
+	}
+
+	s, err := Parse("0 0 0 30 2 1", DayAndWeek())
+	a.Equal(err, ErrImpossible).Nil(s)
+
+	// 无法在解析时检测的组合，Next 在有限的时间内返回零值。
+	s, err = Parse("0 0 0 31 * *", DayOfYear(1))
+	a.NotError(err).NotNil(s)
+	a.True(s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestRegisterDirective(t *testing.T) {
+	a := assert.New(t)
+	defer func() {
+		for _, name := range []string{"@business-hours", "@backup", "@shanghai-9", "@noon-or-daily"} {
+			unregisterDirective(name)
+		}
+	}()
+
+	a.NotError(RegisterDirective("@business-hours", "0 0 9-17 * * 1-5"))
+	s, err := Parse("@business-hours")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "0 0 9-17 * * 1-5")
+	a.Equal(s.Next(time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)), time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC))
+
+	// 多个表达式以及时区，注册成功即可使用。
+	a.NotError(RegisterDirective("@backup", "0 0 3 * * 1-5; 0 0 12 * * 6"))
+	s, err = Parse("@backup")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(time.Date(2020, 1, 3, 4, 0, 0, 0, time.UTC)), time.Date(2020, 1, 4, 12, 0, 0, 0, time.UTC))
+
+	a.NotError(RegisterDirective("@shanghai-9", "CRON_TZ=Asia/Shanghai 0 0 9 * * *"))
+	s, err = Parse("@shanghai-9")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))
+
+	a.NotError(RegisterDirective("@noon-or-daily", "0 0 12 * * 6; @daily"))
+	s, err = Parse("@noon-or-daily; @backup")
+	a.NotError(err).NotNil(s)
+
+	// 重复注册
+	a.Error(RegisterDirective("@business-hours", "0 0 9 * * *"))
+
+	// 与内置指令同名
+	a.Error(RegisterDirective("@daily", "0 0 1 * * *"))
+	a.Error(RegisterDirective("@reboot", "0 0 1 * * *"))
+	a.Error(RegisterDirective("@month-end", "0 0 1 * * *"))
+
+	// 无效的名称
+	a.Error(RegisterDirective("business", "0 0 1 * * *"))
+	a.Error(RegisterDirective("@", "0 0 1 * * *"))
+	a.Error(RegisterDirective("@a b", "0 0 1 * * *"))
+
+	// 无效的表达式
+	a.Error(RegisterDirective("@invalid", "0 0 24 * * *"))
+	a.Error(RegisterDirective("@alias", "@daily"))
+	a.Error(RegisterDirective("@empty", ""))
+	s, err = Parse("@invalid")
+	a.Error(err).Nil(s)
+}
+
+func TestDirectiveArgs(t *testing.T) {
+	a := assert.New(t)
+
+	data := []*struct {
+		name     string
+		min, max int
+		found    bool
+	}{
+		{name: "@daily", found: true},
+		{name: "@reboot", found: true},
+		{name: "@every", min: 1, max: 1, found: true},
+		{name: "@month-end", found: true},
+		{name: "@fiscal-year-end", min: 0, max: 1, found: true},
+		{name: "@not-exists"},
+	}
+	for _, item := range data {
+		min, max, found := DirectiveArgs(item.name)
+		a.Equal(min, item.min, item.name).
+			Equal(max, item.max, item.name).
+			Equal(found, item.found, item.name)
+	}
+
+	a.NotError(RegisterDirective("@args-custom", "0 0 9 * * *"))
+	defer unregisterDirective("@args-custom")
+	_, _, found := DirectiveArgs("@args-custom")
+	a.True(found)
+}
+
+func TestFields(t *testing.T) {
+	a := assert.New(t)
+
+	fs, err := Fields("1-3,10 * 3 * 1,6 5-8")
+	a.Error(err)
+
+	fs, err = Fields("1-3,10 * 3 * 1,6 5-7")
+	a.NotError(err)
+	a.Equal(fs[secondIndex], []uint8{1, 2, 3, 10}).
+		Equal(len(fs[minuteIndex]), 60).
+		Equal(fs[hourIndex], []uint8{3}).
+		Equal(len(fs[dayIndex]), 31).
+		Equal(fs[dayIndex][0], 1).
+		Equal(fs[monthIndex], []uint8{1, 6}).
+		Equal(fs[weekIndex], []uint8{0, 5, 6})
+
+	fs, err = Fields("@daily")
+	a.NotError(err)
+	a.Equal(fs[secondIndex], []uint8{0}).
+		Equal(len(fs[weekIndex]), 7)
+
+	_, err = Fields("@reboot")
+	a.Error(err)
+}
+
+func TestParse_strict(t *testing.T) {
+	a := assert.New(t)
+
+	// 默认模式会忽略多余的空白和逗号
+	s, err := Parse(" 1,2,4,7,  0\t0 * *  * ")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "1,2,4,7, 0 0 * * *")
+
+	s, err = Parse("05 +5 0 * * *")
+	a.NotError(err).NotNil(s)
+
+	s, err = Parse("1,2,4,7 0 0 * * *", Strict())
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "1,2,4,7 0 0 * * *")
+
+	s, err = Parse("0 0 0 1-10/2 * 0", Strict())
+	a.NotError(err).NotNil(s)
+
+	s, err = Parse("@daily", Strict())
+	a.NotError(err).NotNil(s)
+
+	for _, spec := range []string{
+		"1,2,4,7, 0 0 * * *",
+		",1 0 0 * * *",
+		"1,,2 0 0 * * *",
+		"1  0 0 * * *",
+		"1\t0 0 * * *",
+		" 1 0 0 * * *",
+		"1 0 0 * * * ",
+		"05 0 0 * * *",
+		"+5 0 0 * * *",
+		"0 0 0 1-05 * *",
+		"0 0 0 */02 * *",
+	} {
+		s, err = Parse(spec, Strict())
+		a.Error(err, "%s 未返回错误", spec).Nil(s)
+	}
+}
+
+func TestParse_names(t *testing.T) {
+	a := assert.New(t)
+
+	s1, err := Parse("0 0 9 * JAN,JUL MON-FRI")
+	a.NotError(err).NotNil(s1)
+	s2, err := Parse("0 0 9 * 1,7 1-5")
+	a.NotError(err).NotNil(s2)
+	a.Equal(s1.(*cron).data, s2.(*cron).data)
+}
+
+func TestFieldError(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("0 0 0 1 1-13 *")
+	a.Error(err).Nil(s)
+
+	var ferr *FieldError
+	a.True(errors.As(err, &ferr))
+	a.Equal(ferr.Field, "month").
+		Equal(ferr.Index, monthIndex).
+		Equal(ferr.Input, "1-13").
+		Equal(ferr.Min, 1).
+		Equal(ferr.Max, 12).
+		NotNil(errors.Unwrap(err))
+	a.Equal(err.Error(), "字段 month 的值 1-13 无效："+ferr.Err.Error())
+
+	_, err = Parse("0 0 0 32W * *")
+	a.True(errors.As(err, &ferr))
+	a.Equal(ferr.Field, "day")
+
+	a.Equal(ferr.Min, 1).Equal(ferr.Max, 31)
+
+	// L-n 中 n 的范围与日期不同
+	_, err = Parse("0 0 0 L-31 * *")
+	a.True(errors.As(err, &ferr))
+	a.Equal(ferr.Field, "day").
+		Equal(ferr.Input, "L-31").
+		Equal(ferr.Min, 1).
+		Equal(ferr.Max, 30).
+		True(errors.Is(err, ErrOutOfRange))
+
+	_, err = Parse("0 0 0 * * 1#6")
+	a.True(errors.As(err, &ferr))
+	a.Equal(ferr.Field, "week").
+		Equal(ferr.Min, 1).
+		Equal(ferr.Max, 5).
+		True(errors.Is(err, ErrOutOfRange))
+
+	// 非字段错误
+	_, err = Parse("0 0 0 * *")
+	a.Error(err).False(errors.As(err, &ferr))
+}
+
+func TestValidate(t *testing.T) {
+	a := assert.New(t)
+
+	a.NotError(Validate("0 30 9 * * 1-5"))
+	a.NotError(Validate("@daily"))
+	a.Error(Validate("0 0 0 * *"))
+
+	var ferr *FieldError
+	data := []*struct {
+		spec  string
+		index int
+		input string
+		err   error
+	}{
+		{spec: "0 0 0 1 1-13 *", index: monthIndex, input: "1-13", err: ErrOutOfRange},
+		{spec: "0 0 0 1,1 * *", index: dayIndex, input: "1,1", err: ErrDuplicate},
+		{spec: "0 */0 * * * *", index: minuteIndex, input: "*/0", err: ErrInvalidStep},
+		{spec: "*/60 * * * * *", index: secondIndex, input: "*/60", err: ErrInvalidStep},
+		{spec: "0 0 5-3 * * *", index: hourIndex, input: "5-3", err: ErrInvalidRange},
+		{spec: "*,5 0 0 * * *", index: secondIndex, input: "*,5", err: ErrAnyCombined},
+		{spec: "0 0 0 * * 1#6", index: weekIndex, input: "1#6", err: ErrOutOfRange},
+		{spec: "0 0 0 1 1 * 2100", index: yearIndex, input: "2100", err: ErrOutOfRange},
+	}
+	for _, item := range data {
+		err := Validate(item.spec)
+		a.True(errors.As(err, &ferr), item.spec)
+		a.Equal(ferr.Index, item.index, item.spec).
+			Equal(ferr.Input, item.input, item.spec).
+			True(errors.Is(err, item.err), item.spec)
+	}
+}
+
+func TestParse_year(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("0 0 0 1 1 * 2026-2030/2,2035")
+	a.NotError(err).NotNil(s)
+	c := s.(*cron)
+	a.Equal(c.maxYear, 2035).
+		True(c.matchYear(2026)).
+		False(c.matchYear(2027)).
+		True(c.matchYear(2028)).
+		True(c.matchYear(2035)).
+		False(c.matchYear(1969))
+	a.Equal(s.Title(), "0 0 0 1 1 * 2026-2030/2,2035")
+
+	s, err = Parse("0 0 0 1 1 * *")
+	a.NotError(err).NotNil(s)
+	a.Nil(s.(*cron).years)
+
+	var ferr *FieldError
+	for _, spec := range []string{
+		"0 0 0 1 1 * 1969",
+		"0 0 0 1 1 * 2100",
+		"0 0 0 1 1 * 2026,2026",
+		"0 0 0 1 1 * a",
+	} {
+		_, err = Parse(spec)
+		a.True(errors.As(err, &ferr), spec)
+		a.Equal(ferr.Field, "year")
+	}
+
+	_, err = Parse("0 0 0 1 1 * 2026 2027")
+	a.Error(err)
+}
+
+func TestParse_question(t *testing.T) {
+	a := assert.New(t)
+
+	s1, err := Parse("0 0 12 ? * MON")
+	a.NotError(err).NotNil(s1)
+	s2, err := Parse("0 0 12 * * MON")
+	a.NotError(err).NotNil(s2)
+	a.Equal(s1.(*cron).data, s2.(*cron).data)
+	a.Equal(s1.Title(), "0 0 12 ? * MON")
+
+	s1, err = Parse("0 0 12 15 * ?")
+	a.NotError(err).NotNil(s1)
+	next := s1.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2020, 1, 15, 12, 0, 0, 0, time.UTC))
+
+	s1, err = Parse("0 0 12 ? * ?")
+	a.Error(err).Nil(s1)
+
+	s1, err = Parse("0 0 ? 1 * *")
+	a.Error(err).Nil(s1)
+}
+
+func TestParse_every(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := Parse("@every 1h30m")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.Add(90*time.Minute))
+	a.Equal(s.Title(), "每隔 1h30m0s")
+
+	s, err = Parse("@every  10s ")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.Add(10*time.Second))
+
+	s, err = Parse("@every 1x")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@every 1ms") // 小于 1 秒
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@every")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@every 1h", DayOfYear(1))
+	a.Error(err).Nil(s)
+}
+
+func TestParse_hash(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("H H * * * *")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("H H * * * *", Hash(""))
+	a.Error(err).Nil(s)
+
+	s1, err := Parse("H H * * * *", Hash("job1"))
+	a.NotError(err).NotNil(s1)
+	a.Equal(s1.Title(), "H H * * * *")
+
+	s2, err := Parse("H H * * * *", Hash("job1"))
+	a.NotError(err).NotNil(s2)
+	a.Equal(s1.(*cron).data, s2.(*cron).data)
+
+	// 不同的键名，大概率会得到不同的值
+	different := false
+	for i := 0; i < 10; i++ {
+		s2, err = Parse("H H * * * *", Hash(fmt.Sprintf("job-%d", i)))
+		a.NotError(err).NotNil(s2)
+		if s1.(*cron).data[minuteIndex] != s2.(*cron).data[minuteIndex] {
+			different = true
+		}
+	}
+	a.True(different)
+}
+
+func TestParse_union(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("0 0 3 * * 1-5; 0 0 12 * * 6")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "0 0 3 * * 1-5; 0 0 12 * * 6")
+
+	// 2020-01-03 为周五
+	a.Equal(schedulers.NextN(s, time.Date(2020, 1, 3, 4, 0, 0, 0, time.UTC), 3), []time.Time{
+		time.Date(2020, 1, 4, 12, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 3, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 7, 3, 0, 0, 0, time.UTC),
+	})
+
+	// 各自的时区
+	s, err = Parse("CRON_TZ=Asia/Shanghai 0 0 9 * * *;TZ=UTC 0 0 9 * * *")
+	a.NotError(err).NotNil(s)
+	a.Equal(schedulers.NextN(s, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 2), []time.Time{
+		time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+	})
+
+	// 选项作用于所有表达式
+	s, err = Parse("0 0 H * * *; 0 H 12 * * *", Hash("job"))
+	a.NotError(err).NotNil(s)
+
+	s, err = Parse("0 0 3 * * 1-5; 0 0 25 * * *")
+	a.Error(err).Nil(s)
+	var ferr *FieldError
+	a.True(errors.As(err, &ferr)).Equal(ferr.Index, hourIndex)
+	a.True(strings.Contains(err.Error(), "第 2 个表达式"))
+
+	s, err = Parse("0 0 3 * * 1-5;")
+	a.Error(err).Nil(s)
+}
+
+func TestParse_tz(t *testing.T) {
+	a := assert.New(t)
+	loc := time.FixedZone("UTC+8", 8*60*60)
+
+	s, err := Parse("CRON_TZ=Asia/Shanghai 0 0 9 * * *")
+	a.NotError(err).NotNil(s)
+	next := s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)) // 上海的 9 点
+	a.Equal(next.Location(), time.UTC)
+
+	s, err = Parse("TZ=UTC  0 0 9 * * *")
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, loc))
+	a.Equal(next, time.Date(2020, 1, 1, 17, 0, 0, 0, loc)) // UTC 的 9 点
+
+	s, err = Parse("TZ=UTC @daily")
+	a.NotError(err).NotNil(s)
+
+	s, err = Parse("CRON_TZ=Not/Exists 0 0 9 * * *")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("CRON_TZ=UTC")
+	a.Error(err).Nil(s)
+
+	s, err = Parse("CRON_TZ=UTC 0 0 25 * * *")
+	a.Error(err).Nil(s)
+
+	// Lazy
+	s, err = Lazy("CRON_TZ=UTC 0 0 9 * * *")
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, loc))
+	a.Equal(next, time.Date(2020, 1, 1, 17, 0, 0, 0, loc))
+}
@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// Describe 返回 spec 的英文描述
+//
+// 比如 0 30 9 * * 1-5 返回 At 09:30 on weekdays，方便在界面中展示表达式的含义。
+// spec 的格式与 Parse 相同，以分号分隔的多个表达式，各自的描述以 ; or 连接。
+func Describe(spec string) (string, error) {
+	if strings.IndexByte(spec, ';') >= 0 {
+		specs := strings.Split(spec, ";")
+		descs := make([]string, 0, len(specs))
+		for _, item := range specs {
+			desc, err := Describe(strings.TrimSpace(item))
+			if err != nil {
+				return "", err
+			}
+			descs = append(descs, desc)
+		}
+		return strings.Join(descs, "; or "), nil
+	}
+
+	loc, rest, found, err := parseTZ(spec)
+	if err != nil {
+		return "", err
+	}
+
+	s, err := Parse(rest)
+	if err != nil {
+		return "", err
+	}
+
+	var desc string
+	switch c := s.(type) {
+	case *cron:
+		desc = c.describe()
+	case *reboot:
+		desc = "At startup"
+	default:
+		if desc, err = describeDirective(rest); err != nil {
+			return "", err
+		}
+	}
+
+	if found {
+		desc += " (" + loc.String() + ")"
+	}
+	return desc, nil
+}
+
+// 描述 @every、日历相关的指令以及展开之后不是单个 cron 表达式的自定义指令
+func describeDirective(spec string) (string, error) {
+	fs := strings.Fields(spec)
+	switch fs[0] {
+	case "@every":
+		d, err := time.ParseDuration(fs[1])
+		if err != nil {
+			return "", err
+		}
+		return "Every " + describeDuration(d), nil
+	case "@month-end":
+		return "At 00:00 on the last day of the month", nil
+	case "@quarter-end":
+		return "At 00:00 on the last day of the quarter", nil
+	case "@fiscal-year-end":
+		start := time.January
+		if len(fs) > 1 {
+			m, err := strconv.Atoi(fs[1])
+			if err != nil {
+				return "", err
+			}
+			start = time.Month(m)
+		}
+		return "At 00:00 on the last day of the fiscal year starting in " + start.String(), nil
+	}
+
+	directLocker.RLock()
+	d := direct[spec]
+	directLocker.RUnlock()
+	return Describe(d)
+}
+
+// 与 time.Duration.String 相同，但省略末尾为 0 的分和秒，比如 1h30m0s 返回 1h30m。
+func describeDuration(d time.Duration) string {
+	str := d.String()
+	if strings.HasSuffix(str, "m0s") {
+		str = strings.TrimSuffix(str, "0s")
+	}
+	if strings.HasSuffix(str, "h0m") {
+		str = strings.TrimSuffix(str, "0m")
+	}
+	return str
+}
+
+func (c *cron) describe() string {
+	parts := []string{c.describeTime()}
+
+	if d := c.describeDay(); d != "" {
+		parts = append(parts, d)
+	}
+
+	if c.weekOfMonth != 0 {
+		parts = append(parts, "in "+describeFields("week", c.weekOfMonth, bound{min: 1, max: 5}, strconv.Itoa)+" of the month")
+	}
+
+	if c.dayOfYear != nil {
+		vals := make([]int, 0, 10)
+		for d, ok := range c.dayOfYear {
+			if ok {
+				vals = append(vals, d)
+			}
+		}
+		parts = append(parts, "on "+describeValues("day", vals, strconv.Itoa)+" of the year")
+	}
+
+	if m := c.data[monthIndex]; m != any && m != step {
+		parts = append(parts, "in "+describeFields("", m, bounds[monthIndex], func(v int) string {
+			return time.Month(v).String()
+		}))
+	}
+
+	if c.years != nil {
+		vals := make([]int, 0, 10)
+		for i, ok := range c.years {
+			if ok {
+				vals = append(vals, i+bounds[yearIndex].min)
+			}
+		}
+		parts = append(parts, "in "+describeValues("", vals, strconv.Itoa))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// 描述时间部分
+func (c *cron) describeTime() string {
+	sec, min, hour := c.data[secondIndex], c.data[minuteIndex], c.data[hourIndex]
+
+	if single(sec) && single(min) && single(hour) {
+		if sec.min() == 0 {
+			return fmt.Sprintf("At %02d:%02d", hour.min(), min.min())
+		}
+		return fmt.Sprintf("At %02d:%02d:%02d", hour.min(), min.min(), sec.min())
+	}
+
+	phrases := make([]string, 0, 3)
+	hasStep := false
+	for _, item := range []struct {
+		name string
+		fs   fields
+		b    bound
+	}{
+		{"second", sec, bounds[secondIndex]},
+		{"minute", min, bounds[minuteIndex]},
+		{"hour", hour, bounds[hourIndex]},
+	} {
+		switch item.fs {
+		case any:
+		case step:
+			if !hasStep { // 更小的单位已经是每一次，不需要再描述。
+				phrases = append(phrases, "every "+item.name)
+				hasStep = true
+			}
+		default:
+			phrases = append(phrases, describeFields(item.name, item.fs, item.b, strconv.Itoa))
+		}
+	}
+
+	desc := strings.Join(phrases, ", ")
+	if strings.HasPrefix(desc, "every ") {
+		return "E" + desc[1:]
+	}
+	return "At " + desc
+}
+
+// 描述日和星期部分
+func (c *cron) describeDay() string {
+	days, weeks := c.data[dayIndex], c.data[weekIndex]
+
+	var items []string
+	if days != any && days != step {
+		if vals := days.values(bounds[dayIndex]); len(vals) > 0 {
+			items = append(items, describeFields("day", days, bounds[dayIndex], strconv.Itoa)+" of the month")
+		}
+		if days&last != 0 {
+			items = append(items, "the last day of the month")
+		}
+		for _, v := range c.nearest.values(bounds[dayIndex]) {
+			items = append(items, fmt.Sprintf("the weekday nearest day %d", v))
+		}
+		if c.nearest&last != 0 {
+			items = append(items, "the last weekday of the month")
+		}
+		for _, v := range c.beforeLast.values(bound{min: 1, max: 30}) {
+			if v == 1 {
+				items = append(items, "the day before the last day of the month")
+			} else {
+				items = append(items, fmt.Sprintf("%d days before the last day of the month", v))
+			}
+		}
+	}
+
+	if weeks != any && weeks != step {
+		b := bound{min: 0, max: 6}
+		switch vals := weeks.values(b); {
+		case len(vals) == 0:
+		case weeks == pow(1, 2, 3, 4, 5):
+			items = append(items, "weekdays")
+		case weeks == pow(0, 6):
+			items = append(items, "weekends")
+		default:
+			items = append(items, describeFields("", weeks, b, func(v int) string {
+				return time.Weekday(v).String()
+			}))
+		}
+	}
+
+	for w, nth := range c.nth {
+		for _, n := range nth.values(bound{min: 1, max: 5}) {
+			items = append(items, fmt.Sprintf("the %s %s of the month", ordinals[n], time.Weekday(w)))
+		}
+
+		if nth&last != 0 {
+			items = append(items, fmt.Sprintf("the last %s of the month", time.Weekday(w)))
+		}
+	}
+
+	if len(items) == 0 {
+		return ""
+	}
+	return "on " + strings.Join(items, " or ")
+}
+
+// 是否仅包含一个值
+func single(fs fields) bool {
+	return fs != any && fs != step && fs&(fs-1) == 0
+}
+
+func pow(vals ...uint64) fields {
+	var fs fields
+	for _, v := range vals {
+		fs |= 1 << v
+	}
+	return fs
+}
+
+func describeFields(name string, fs fields, b bound, format func(int) string) string {
+	vals := make([]int, 0, 10)
+	for _, v := range fs.values(b) {
+		vals = append(vals, int(v))
+	}
+	return describeValues(name, vals, format)
+}
+
+// 描述 vals 中的值，连续三个以上的值以范围表示，比如 days 1 through 5 and 10
+//
+// name 为值的名称，多个值时会在其后加上 s，为空表示不需要名称。
+func describeValues(name string, vals []int, format func(int) string) string {
+	items := make([]string, 0, len(vals))
+	for i := 0; i < len(vals); {
+		j := i
+		for j+1 < len(vals) && vals[j+1] == vals[j]+1 {
+			j++
+		}
+
+		if j-i >= 2 {
+			items = append(items, format(vals[i])+" through "+format(vals[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, format(vals[k]))
+			}
+		}
+		i = j + 1
+	}
+
+	var list string
+	switch len(items) {
+	case 0:
+	case 1:
+		list = items[0]
+	default:
+		list = strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	}
+
+	switch {
+	case name == "":
+		return list
+	case len(vals) > 1:
+		return name + "s " + list
+	default:
+		return name + " " + list
+	}
+}
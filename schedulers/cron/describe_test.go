@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	a := assert.New(t)
+
+	data := []struct {
+		spec, desc string
+	}{
+		{spec: "0 30 9 * * 1-5", desc: "At 09:30 on weekdays"},
+		{spec: "0 0 3 * * 1-5; 0 0 12 * * 6", desc: "At 03:00 on weekdays; or At 12:00 on Saturday"},
+		{spec: "5 30 9 * * SAT,SUN", desc: "At 09:30:05 on weekends"},
+		{spec: "0 0 0 1,15 * *", desc: "At 00:00 on days 1 and 15 of the month"},
+		{spec: "0 0 0 * * 1,3,5", desc: "At 00:00 on Monday, Wednesday and Friday"},
+		{spec: "0 0 0 * * 2-4", desc: "At 00:00 on Tuesday through Thursday"},
+		{spec: "0 0 0 1 * 1", desc: "At 00:00 on day 1 of the month or Monday"},
+		{spec: "0 0 0 L * *", desc: "At 00:00 on the last day of the month"},
+		{spec: "0 0 0 15W,LW * *", desc: "At 00:00 on the weekday nearest day 15 or the last weekday of the month"},
+		{spec: "0 0 0 * * 5#3", desc: "At 00:00 on the third Friday of the month"},
+		{spec: "0 0 0 * * 5L", desc: "At 00:00 on the last Friday of the month"},
+		{spec: "0 0 0 L-3 * *", desc: "At 00:00 on 3 days before the last day of the month"},
+		{spec: "0 0 12 1 1-3,7 *", desc: "At 12:00 on day 1 of the month in January through March and July"},
+		{spec: "0 0 0 1 1 * 2026-2028", desc: "At 00:00 on day 1 of the month in January in 2026 through 2028"},
+		{spec: "*/15 * * * * *", desc: "At seconds 0, 15, 30 and 45, every minute"},
+		{spec: "0 */30 9-17 * * *", desc: "At second 0, minutes 0 and 30, hours 9 through 17"},
+		{spec: "* 5 * * * *", desc: "At minute 5, every hour"},
+		{spec: "@daily", desc: "At 00:00"},
+		{spec: "@reboot", desc: "At startup"},
+		{spec: "@every 1h", desc: "Every 1h"},
+		{spec: "@every 90m", desc: "Every 1h30m"},
+		{spec: "@every 1h0m30s", desc: "Every 1h0m30s"},
+		{spec: "@month-end", desc: "At 00:00 on the last day of the month"},
+		{spec: "@quarter-end", desc: "At 00:00 on the last day of the quarter"},
+		{spec: "@fiscal-year-end", desc: "At 00:00 on the last day of the fiscal year starting in January"},
+		{spec: "@fiscal-year-end 4", desc: "At 00:00 on the last day of the fiscal year starting in April"},
+		{spec: "CRON_TZ=UTC 0 30 9 * * *", desc: "At 09:30 (UTC)"},
+	}
+
+	for _, item := range data {
+		desc, err := Describe(item.spec)
+		a.NotError(err, "%s 出错 %s", item.spec, err).
+			Equal(desc, item.desc)
+	}
+
+	desc, err := Describe("0 0 25 * * *")
+	a.Error(err).Empty(desc)
+
+	desc, err = Describe("CRON_TZ=Not/Exists 0 0 0 * * *")
+	a.Error(err).Empty(desc)
+
+	// 展开之后为多个表达式的自定义指令
+	a.NotError(RegisterDirective("@describe-union", "0 0 3 * * 1-5; CRON_TZ=UTC 0 0 12 * * 6"))
+	defer unregisterDirective("@describe-union")
+	desc, err = Describe("@describe-union")
+	a.NotError(err).Equal(desc, "At 03:00 on weekdays; or At 12:00 on Saturday (UTC)")
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import "github.com/issue9/scheduled/schedulers"
+
+// Expr 可直接用于配置文件中的 cron 表达式
+//
+// 实现了 encoding.TextMarshaler 和 encoding.TextUnmarshaler 接口，
+// 可以作为 JSON、YAML、TOML 等配置对象的字段，比如：
+//  type Config struct {
+//      Backup cron.Expr `json:"backup"`
+//  }
+// 解析之后可以直接作为 schedulers.Scheduler 使用。
+// 表达式的格式与 Parse 相同，但无法指定 Option。
+// 零值的 Scheduler 为 nil，不能直接使用，其文本形式为空字符串，反之亦然，
+// 方便配置中未设置的字段可以正常地保存和加载。
+type Expr struct {
+	schedulers.Scheduler
+	spec string
+}
+
+// ParseExpr 解析 spec 并返回 Expr
+//
+// 参数与 Parse 相同，opts 不会体现在 MarshalText 的返回值中。
+func ParseExpr(spec string, opts ...Option) (Expr, error) {
+	s, err := Parse(spec, opts...)
+	if err != nil {
+		return Expr{}, err
+	}
+	return Expr{Scheduler: s, spec: spec}, nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口，返回原始的表达式。
+func (e Expr) MarshalText() ([]byte, error) {
+	return []byte(e.spec), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+//
+// data 为空时，e 被设置为零值。
+func (e *Expr) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*e = Expr{}
+		return nil
+	}
+
+	expr, err := ParseExpr(string(data))
+	if err != nil {
+		return err
+	}
+	*e = expr
+	return nil
+}
+
+// String 返回原始的表达式
+func (e Expr) String() string {
+	return e.spec
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var (
+	_ schedulers.Scheduler     = Expr{}
+	_ fmt.Stringer             = Expr{}
+	_ encoding.TextMarshaler   = Expr{}
+	_ encoding.TextUnmarshaler = &Expr{}
+)
+
+func TestExpr(t *testing.T) {
+	a := assert.New(t)
+
+	type config struct {
+		Backup Expr  `json:"backup"`
+		Report *Expr `json:"report,omitempty"`
+	}
+
+	conf := &config{}
+	a.NotError(json.Unmarshal([]byte(`{"backup":"0 0 3 * * 1-5; 0 0 12 * * 6","report":"@daily"}`), conf))
+	a.Equal(conf.Backup.String(), "0 0 3 * * 1-5; 0 0 12 * * 6").
+		Equal(conf.Backup.Next(time.Date(2020, 1, 3, 4, 0, 0, 0, time.UTC)), time.Date(2020, 1, 4, 12, 0, 0, 0, time.UTC))
+	a.Equal(conf.Report.Title(), "0 0 0 * * *")
+
+	data, err := json.Marshal(conf)
+	a.NotError(err).
+		Equal(string(data), `{"backup":"0 0 3 * * 1-5; 0 0 12 * * 6","report":"@daily"}`)
+
+	a.Error(json.Unmarshal([]byte(`{"backup":"0 0 25 * * *"}`), conf))
+
+	// 零值
+	data, err = json.Marshal(&config{})
+	a.NotError(err).Equal(string(data), `{"backup":""}`)
+	conf = &config{Backup: conf.Backup}
+	a.NotError(json.Unmarshal(data, conf))
+	a.Nil(conf.Backup.Scheduler).Equal(conf.Backup.String(), "").Nil(conf.Report)
+
+	e, err := ParseExpr("H 0 0 * * *", Hash("job"))
+	a.NotError(err).NotNil(e.Scheduler)
+	_, err = ParseExpr("H 0 0 * * *")
+	a.Error(err)
+}
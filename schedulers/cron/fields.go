@@ -3,7 +3,9 @@
 package cron
 
 import (
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/bits"
 	"sort"
 	"strconv"
@@ -31,6 +33,9 @@ const (
 	// step 表示当前字段是允许范围内的所有值。
 	// 每次计算时，按其当前值加 1 即可。
 	step fields = 1 << 62
+
+	// last 表示每月的最后一天，仅用于日字段。
+	last fields = 1 << 60
 )
 
 var bounds = []bound{
@@ -40,16 +45,104 @@ var bounds = []bound{
 	{min: 1, max: 31}, // dayIndex
 	{min: 1, max: 12}, // monthIndex
 	{min: 0, max: 7},  // weekIndex
+
+	{min: 1970, max: 2099}, // yearIndex
 }
 
 type bound struct{ min, max int }
 
+// 带有取值范围的错误
+//
+// 用于字段中某部分的取值范围与字段本身不同的情况，比如 L-n 中的 n，
+// newFieldError 会以此处的范围作为 FieldError 的 Min 和 Max。
+type boundError struct {
+	bound
+	err error
+}
+
+func (b bound) outOfRange(n int) *boundError {
+	return &boundError{
+		bound: b,
+		err:   fmt.Errorf("值 %d %w：[%d,%d]", n, ErrOutOfRange, b.min, b.max),
+	}
+}
+
+func (e *boundError) Error() string { return e.err.Error() }
+
+func (e *boundError) Unwrap() error { return e.err }
+
+// 字段解析时的常见错误原因，可以通过 errors.Is 与 FieldError.Err 进行比较。
+var (
+	ErrOutOfRange   = errors.New("超出范围")
+	ErrDuplicate    = errors.New("重复的值")
+	ErrInvalidStep  = errors.New("无效的步长")
+	ErrInvalidRange = errors.New("起始值大于结束值")
+	ErrAnyCombined  = errors.New("* 不能与其它值组合")
+)
+
+// 表示由用户自定义的字段，参考 ParseValues。
+const customIndex = -1
+
+// ParseValues 以 cron 表达式中数值字段的语法分析自定义的字段
+//
+// 可用于在相同的语法之上实现特定领域的表达式，比如班次 1-3、产线 1-12 等。
+// field 支持 *、-、, 以及 / 等语法，[min,max] 为取值范围，max 不能大于 59。
+// 返回按从小到大排序的所有值，* 表示范围内的所有值。
+func ParseValues(field string, min, max int) ([]int, error) {
+	if min < 0 || max > 59 || min > max {
+		return nil, fmt.Errorf("无效的取值范围：[%d,%d]", min, max)
+	}
+
+	b := bound{min: min, max: max}
+	fs, err := parseBoundField(customIndex, b, field)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := fs.values(b)
+	ret := make([]int, 0, len(vals))
+	for _, v := range vals {
+		ret = append(ret, int(v))
+	}
+	return ret, nil
+}
+
+// 月份和星期的名称，不区分大小写。
+var names = map[int]map[string]int{
+	monthIndex: {
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	},
+	weekIndex: {
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	},
+}
+
+// 将 v 转换成 typ 字段中的数值，月份和星期字段可以使用名称。
+func parseValue(typ int, v string) (int, error) {
+	if n, found := names[typ][strings.ToUpper(v)]; found {
+		return n, nil
+	}
+	return strconv.Atoi(v)
+}
+
 func (b bound) valid(v int) bool {
 	return v >= b.min && v <= b.max
 }
 
 // 第一个非零值
 
+// 返回 fs 中在 b 范围内的所有值
+func (fs fields) values(b bound) []uint8 {
+	vals := make([]uint8, 0, b.max-b.min+1)
+	for i := b.min; i <= b.max; i++ {
+		if fs.match(i) {
+			vals = append(vals, uint8(i))
+		}
+	}
+	return vals
+}
+
 // 获取 fields 中与 curr 最近的下一个值
 //
 // curr 当前的时间值；
@@ -94,7 +187,20 @@ func (fs fields) next(curr int, b bound, greater bool) (val int, c bool) {
 //  n1-n2
 //  n1,n2
 //  n1-n2,n3-n4,n5
+//  */n
+//  n1-n2/n
+//  n1/n 等同于 n1-max/n
+//  L 仅用于日字段，表示每月的最后一天，可以与其它值组合，比如 1,15,L
+// 月份和星期字段中的数值可以使用英文名称的缩写代替，比如 MON-FRI 和 JAN,JUL。
+// 星期字段中的 0 和 7 都表示周日，可以同时出现，比如 0-7 和 5-7。
 func parseField(typ int, field string) (fields, error) {
+	return parseBoundField(typ, bounds[typ], field)
+}
+
+// 以 b 为取值范围分析单个数字域的内容
+//
+// typ 为 customIndex 时，表示由用户自定义的字段。
+func parseBoundField(typ int, b bound, field string) (fields, error) {
 	if field == "*" {
 		return any, nil
 	}
@@ -102,69 +208,312 @@ func parseField(typ int, field string) (fields, error) {
 	fs := strings.FieldsFunc(field, func(r rune) bool { return r == ',' })
 	list := make([]uint64, 0, len(fs))
 
-	b := bounds[typ]
+	var ret fields
 	for _, v := range fs {
-		if len(v) <= 2 { // 少于 3 个字符，说明不可能有特殊字符。
-			n, err := strconv.Atoi(v)
-			if err != nil {
-				return 0, err
+		if typ == dayIndex && v == "L" {
+			if ret&last != 0 {
+				return 0, fmt.Errorf("%w L", ErrDuplicate)
 			}
+			ret |= last
+			continue
+		}
 
-			if !b.valid(n) {
-				return 0, fmt.Errorf("值 %d 超出范围：[%d,%d]", n, b.min, b.max)
+		n1, n2, inc, err := parseRange(typ, b, v)
+		if err != nil {
+			return 0, err
+		}
+
+		for i := n1; i <= n2; i += inc {
+			list = append(list, uint64(i))
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+
+	for i := 1; i < len(list); i++ {
+		if list[i] == list[i-1] {
+			return 0, fmt.Errorf("%w %d", ErrDuplicate, list[i])
+		}
+	}
+
+	for _, v := range list {
+		if typ == weekIndex && v == uint64(b.max) { // 星期中的 7 替换成 0，允许与 0 同时出现，比如 0-7。
+			v = uint64(b.min)
+		}
+		ret |= (1 << v)
+	}
+	return ret, nil
+}
+
+// 分析日字段的内容
+//
+// 在 parseField 的基础上增加了对 W 和 L-n 的支持：
+//  nW 表示离 n 日最近的工作日，比如 15W
+//  LW 表示每月的最后一个工作日
+//  L-n 表示每月最后一天之前的第 n 天，比如 L-3，n 的取值范围为 [1,30]
+// nearest 保存了所有 W 相关的值，其中 LW 以 last 表示；beforeLast 保存了所有 L-n 中的 n。
+func parseDayField(field string) (days, nearest, beforeLast fields, err error) {
+	fs := strings.FieldsFunc(field, func(r rune) bool { return r == ',' })
+	others := make([]string, 0, len(fs))
+
+	for _, v := range fs {
+		if strings.HasPrefix(v, "L-") {
+			n, err := strconv.Atoi(v[2:])
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if b := (bound{min: 1, max: 30}); !b.valid(n) {
+				return 0, 0, 0, b.outOfRange(n)
 			}
 
-			// 星期中的 7 替换成 0
-			if typ == weekIndex && n == b.max {
-				n = b.min
+			if beforeLast.match(n) {
+				return 0, 0, 0, fmt.Errorf("%w %s", ErrDuplicate, v)
 			}
+			beforeLast |= 1 << uint64(n)
+			continue
+		}
 
-			list = append(list, uint64(n))
+		if !strings.HasSuffix(v, "W") {
+			others = append(others, v)
 			continue
 		}
 
-		index := strings.IndexByte(v, '-')
-		if index >= 0 {
-			n1, err := strconv.Atoi(v[:index])
+		bit := last
+		if v != "LW" {
+			n, err := strconv.Atoi(v[:len(v)-1])
 			if err != nil {
-				return 0, err
+				return 0, 0, 0, err
 			}
-			n2, err := strconv.Atoi(v[index+1:])
-			if err != nil {
-				return 0, err
+			if b := bounds[dayIndex]; !b.valid(n) {
+				return 0, 0, 0, fmt.Errorf("值 %d %w：[%d,%d]", n, ErrOutOfRange, b.min, b.max)
 			}
+			bit = 1 << uint64(n)
+		}
+
+		if nearest&bit != 0 {
+			return 0, 0, 0, fmt.Errorf("%w %s", ErrDuplicate, v)
+		}
+		nearest |= bit
+	}
 
-			if !b.valid(n1) {
-				return 0, fmt.Errorf("值 %d 超出范围：[%d,%d]", n1, b.min, b.max)
+	if len(others) > 0 {
+		if days, err = parseField(dayIndex, strings.Join(others, ",")); err != nil {
+			return 0, 0, 0, err
+		}
+		if days == any && (nearest != 0 || beforeLast != 0) {
+			return 0, 0, 0, ErrAnyCombined
+		}
+	}
+	return days, nearest, beforeLast, nil
+}
+
+// 分析星期字段的内容
+//
+// 在 parseField 的基础上增加了对 # 和 L 的支持：
+//  w#n 表示每月第 n 个星期 w，比如 5#3 表示每月的第三个周五，n 的取值范围为 [1,5]；
+//  wL 表示每月最后一个星期 w，比如 5L 表示每月的最后一个周五。
+// nth 的下标为星期，值中保存了对应的 n，其中 wL 以 last 表示。
+func parseWeekField(field string) (weeks fields, nth [7]fields, err error) {
+	fs := strings.FieldsFunc(field, func(r rune) bool { return r == ',' })
+	others := make([]string, 0, len(fs))
+
+	for _, v := range fs {
+		var w, n int
+		var bit fields
+		switch index := strings.IndexByte(v, '#'); {
+		case index >= 0:
+			if w, err = parseWeekday(v[:index]); err != nil {
+				return 0, nth, err
 			}
 
-			if !b.valid(n2) {
-				return 0, fmt.Errorf("值 %d 超出范围：[%d,%d]", n2, b.min, b.max)
+			if n, err = strconv.Atoi(v[index+1:]); err != nil {
+				return 0, nth, err
 			}
+			if b := (bound{min: 1, max: 5}); !b.valid(n) {
+				return 0, nth, b.outOfRange(n)
+			}
+			bit = 1 << uint64(n)
+		case len(v) > 1 && v[len(v)-1] == 'L':
+			if w, err = parseWeekday(v[:len(v)-1]); err != nil {
+				return 0, nth, err
+			}
+			bit = last
+		default:
+			others = append(others, v)
+			continue
+		}
+
+		if nth[w]&bit != 0 {
+			return 0, nth, fmt.Errorf("%w %s", ErrDuplicate, v)
+		}
+		nth[w] |= bit
+	}
+
+	if len(others) > 0 {
+		if weeks, err = parseField(weekIndex, strings.Join(others, ",")); err != nil {
+			return 0, nth, err
+		}
+		if weeks == any && len(others) < len(fs) {
+			return 0, nth, ErrAnyCombined
+		}
+	}
+	return weeks, nth, nil
+}
+
+// 分析星期字段中单个的星期值，7 会被转换成 0。
+func parseWeekday(v string) (int, error) {
+	w, err := parseValue(weekIndex, v)
+	if err != nil {
+		return 0, err
+	}
+
+	if b := bounds[weekIndex]; !b.valid(w) {
+		return 0, fmt.Errorf("值 %d %w：[%d,%d]", w, ErrOutOfRange, b.min, b.max)
+	}
+
+	if w == 7 {
+		w = 0
+	}
+	return w, nil
+}
+
+// 将 field 中的 H 替换成由 key 计算出的值
+//
+// H 可以是以下格式：
+//  H 表示取值范围内的某一个值
+//  H/n 表示以某一个小于 n 的值为起始值，步长为 n
+func replaceHash(typ int, field, key string) (string, error) {
+	b := bounds[typ]
+	switch typ {
+	case dayIndex: // 保证每个月都会执行
+		b.max = 28
+	case weekIndex: // 7 与 0 相同
+		b.max = 6
+	}
+
+	fs := strings.Split(field, ",")
+	for i, v := range fs {
+		if v != "H" && !strings.HasPrefix(v, "H/") {
+			continue
+		}
+
+		if key == "" {
+			return "", errors.New("使用 H 时必须通过 Hash 指定键名")
+		}
+
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		h.Write([]byte{byte(typ)})
+		sum := int(h.Sum32() & 0x7fffffff)
+
+		if v == "H" {
+			fs[i] = strconv.Itoa(b.min + sum%(b.max-b.min+1))
+			continue
+		}
+
+		inc, err := strconv.Atoi(v[2:])
+		if err != nil {
+			return "", err
+		}
+		if inc <= 0 || inc > b.max-b.min {
+			return "", fmt.Errorf("%w %d，取值范围：[%d,%d]", ErrInvalidStep, inc, b.min, b.max)
+		}
+		fs[i] = fmt.Sprintf("%d-%d/%d", b.min+sum%inc, b.max, inc)
+	}
+	return strings.Join(fs, ","), nil
+}
+
+// 分析年份字段的内容
+//
+// 格式与 parseField 相同，但年份超出了 fields 所能表示的范围，
+// 所以返回以与最小年份的差值为下标的数组，* 返回 nil，max 为其中最大的年份。
+func parseYearField(field string) (years []bool, max int, err error) {
+	if field == "*" {
+		return nil, 0, nil
+	}
+
+	b := bounds[yearIndex]
+	years = make([]bool, b.max-b.min+1)
+	for _, v := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' }) {
+		n1, n2, inc, err := parseRange(yearIndex, b, v)
+		if err != nil {
+			return nil, 0, err
+		}
 
-			for i := n1; i <= n2; i++ {
-				if typ == weekIndex && i == b.max {
-					list = append(list, uint64(b.min))
-				} else {
-					list = append(list, uint64(i))
-				}
+		for i := n1; i <= n2; i += inc {
+			if years[i-b.min] {
+				return nil, 0, fmt.Errorf("%w %d", ErrDuplicate, i)
+			}
+			years[i-b.min] = true
+			if i > max {
+				max = i
 			}
 		}
 	}
 
-	sort.SliceStable(list, func(i, j int) bool {
-		return list[i] < list[j]
-	})
+	return years, max, nil
+}
 
-	for i := 1; i < len(list); i++ {
-		if list[i] == list[i-1] {
-			return 0, fmt.Errorf("重复的值 %d", list[i])
+// 分析 parseField 中以逗号分隔的单个值
+//
+// 返回的 [n1,n2] 为取值范围，inc 为步长。
+func parseRange(typ int, b bound, v string) (n1, n2, inc int, err error) {
+	inc = 1
+
+	hasStep := false
+	if index := strings.IndexByte(v, '/'); index >= 0 {
+		if inc, err = strconv.Atoi(v[index+1:]); err != nil {
+			return 0, 0, 0, err
 		}
+		if inc <= 0 {
+			return 0, 0, 0, fmt.Errorf("%w %d，必须大于 0", ErrInvalidStep, inc)
+		}
+		if inc > b.max-b.min { // 超出整个字段的范围，大多是书写错误。
+			return 0, 0, 0, fmt.Errorf("%w %d，取值范围：[%d,%d]", ErrInvalidStep, inc, b.min, b.max)
+		}
+		v = v[:index]
+		hasStep = true
 	}
 
-	var ret fields
-	for _, v := range list {
-		ret |= (1 << v)
+	switch index := strings.IndexByte(v, '-'); {
+	case v == "*":
+		if !hasStep {
+			return 0, 0, 0, ErrAnyCombined
+		}
+		n1, n2 = b.min, b.max
+		if typ == weekIndex { // 7 与 0 相同，不需要重复
+			n2--
+		}
+	case index >= 0:
+		if n1, err = parseValue(typ, v[:index]); err != nil {
+			return 0, 0, 0, err
+		}
+		if n2, err = parseValue(typ, v[index+1:]); err != nil {
+			return 0, 0, 0, err
+		}
+	default:
+		if n1, err = parseValue(typ, v); err != nil {
+			return 0, 0, 0, err
+		}
+		n2 = n1
+		if hasStep {
+			n2 = b.max
+		}
 	}
-	return ret, nil
+
+	if !b.valid(n1) {
+		return 0, 0, 0, fmt.Errorf("值 %d %w：[%d,%d]", n1, ErrOutOfRange, b.min, b.max)
+	}
+
+	if !b.valid(n2) {
+		return 0, 0, 0, fmt.Errorf("值 %d %w：[%d,%d]", n2, ErrOutOfRange, b.min, b.max)
+	}
+
+	if n1 > n2 {
+		return 0, 0, 0, fmt.Errorf("%w：%d-%d", ErrInvalidRange, n1, n2)
+	}
+
+	return n1, n2, inc, nil
 }
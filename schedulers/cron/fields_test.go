@@ -3,11 +3,82 @@
 package cron
 
 import (
+	"fmt"
+	"math/bits"
+	"strconv"
 	"testing"
 
 	"github.com/issue9/assert"
 )
 
+func TestParseDayField(t *testing.T) {
+	a := assert.New(t)
+
+	days, nearest, _, err := parseDayField("*")
+	a.NotError(err).Equal(days, any).Equal(nearest, 0)
+
+	days, nearest, _, err = parseDayField("1,15W,LW")
+	a.NotError(err).Equal(days, pow2(1)).Equal(nearest, pow2(15)|last)
+
+	days, nearest, _, err = parseDayField("15W")
+	a.NotError(err).Equal(days, 0).Equal(nearest, pow2(15))
+
+	_, _, _, err = parseDayField("32W")
+	a.Error(err)
+
+	_, _, _, err = parseDayField("0W")
+	a.Error(err)
+
+	_, _, _, err = parseDayField("aW")
+	a.Error(err)
+
+	_, _, _, err = parseDayField("15W,15W")
+	a.Error(err)
+
+	_, _, _, err = parseDayField("1,32")
+	a.Error(err)
+
+	_, _, _, err = parseDayField("*,15W")
+	a.Error(err)
+
+	days, nearest, beforeLast, err := parseDayField("1,L-3,L,L-1")
+	a.NotError(err).
+		Equal(days, pow2(1)|last).
+		Equal(nearest, 0).
+		Equal(beforeLast, pow2(1, 3))
+
+	for _, field := range []string{"L-0", "L-31", "L-a", "L-", "L-3,L-3", "*,L-3"} {
+		_, _, _, err = parseDayField(field)
+		a.Error(err, "%s 未返回错误", field)
+	}
+}
+
+func TestParseWeekField(t *testing.T) {
+	a := assert.New(t)
+
+	weeks, nth, err := parseWeekField("*")
+	a.NotError(err).Equal(weeks, any).Equal(nth, [7]fields{})
+
+	weeks, nth, err = parseWeekField("1#2,FRI#3,5#1,7#5,sat")
+	a.NotError(err).Equal(weeks, pow2(6))
+	a.Equal(nth[1], pow2(2)).
+		Equal(nth[5], pow2(1, 3)).
+		Equal(nth[0], pow2(5))
+
+	weeks, nth, err = parseWeekField("5L,FRIL,1#1,SUNL")
+	a.Error(err)
+	weeks, nth, err = parseWeekField("5L,1#1,1L,7L")
+	a.NotError(err).Equal(weeks, fields(0))
+	a.Equal(nth[5], last).
+		Equal(nth[1], pow2(1)|last).
+		Equal(nth[0], last)
+
+	for _, field := range []string{"8#1", "1#0", "1#6", "a#1", "1#a", "1#2,1#2", "*,1#2", "L", "8L", "*L", "0L,7L"} {
+		_, _, err = parseWeekField(field)
+		a.Error(err, "%s 未返回错误", field)
+	}
+}
+
 func TestParseField(t *testing.T) {
 	a := assert.New(t)
 
@@ -72,8 +143,28 @@ func TestParseField(t *testing.T) {
 			vals:  pow2(0),
 		},
 		{ // 0 与 7 是相同的值
+			typ:   weekIndex,
+			field: "0-7",
+			vals:  pow2(0, 1, 2, 3, 4, 5, 6),
+		},
+		{
+			typ:   weekIndex,
+			field: "0,7",
+			vals:  pow2(0),
+		},
+		{
+			typ:   weekIndex,
+			field: "5-7",
+			vals:  pow2(0, 5, 6),
+		},
+		{
+			typ:   weekIndex,
+			field: "1-7/2",
+			vals:  pow2(0, 1, 3, 5),
+		},
+		{
 			typ:    weekIndex,
-			field:  "0-7",
+			field:  "7,7",
 			hasErr: true,
 		},
 		{ // 超出范围
@@ -98,6 +189,193 @@ func TestParseField(t *testing.T) {
 			field:  "0-4",
 			hasErr: true,
 		},
+
+		// 步长相关的测试
+		{
+			typ:   secondIndex,
+			field: "0,15,30,45",
+			vals:  pow2(0, 15, 30, 45),
+		},
+		{
+			typ:   secondIndex,
+			field: "*/20",
+			vals:  pow2(0, 20, 40),
+		},
+		{
+			typ:   secondIndex,
+			field: "10-50/10",
+			vals:  pow2(10, 20, 30, 40, 50),
+		},
+		{
+			typ:   secondIndex,
+			field: "5/15",
+			vals:  pow2(5, 20, 35, 50),
+		},
+		{
+			typ:   secondIndex,
+			field: "1-4/2,30",
+			vals:  pow2(1, 3, 30),
+		},
+		{
+			typ:   hourIndex,
+			field: "*/6",
+			vals:  pow2(0, 6, 12, 18),
+		},
+		{
+			typ:   dayIndex,
+			field: "*/10",
+			vals:  pow2(1, 11, 21, 31),
+		},
+		{
+			typ:   monthIndex,
+			field: "*/3",
+			vals:  pow2(1, 4, 7, 10),
+		},
+		{
+			typ:   weekIndex,
+			field: "*/1",
+			vals:  pow2(0, 1, 2, 3, 4, 5, 6),
+		},
+		{
+			typ:   weekIndex,
+			field: "5/2",
+			vals:  pow2(0, 5),
+		},
+		{ // 步长为 0
+			typ:    secondIndex,
+			field:  "*/0",
+			hasErr: true,
+		},
+		{
+			typ:   dayIndex,
+			field: "L",
+			vals:  last,
+		},
+		{
+			typ:   dayIndex,
+			field: "1,L",
+			vals:  pow2(1) | last,
+		},
+		{ // L 仅用于日字段
+			typ:    secondIndex,
+			field:  "L",
+			hasErr: true,
+		},
+		{
+			typ:    dayIndex,
+			field:  "L,L",
+			hasErr: true,
+		},
+		{ // 名称
+			typ:   monthIndex,
+			field: "JAN,jul,Dec",
+			vals:  pow2(1, 7, 12),
+		},
+		{
+			typ:   weekIndex,
+			field: "MON-FRI",
+			vals:  pow2(1, 2, 3, 4, 5),
+		},
+		{
+			typ:   weekIndex,
+			field: "sun,sat",
+			vals:  pow2(0, 6),
+		},
+		{
+			typ:   weekIndex,
+			field: "MON-FRI/2",
+			vals:  pow2(1, 3, 5),
+		},
+		{ // 名称仅用于月份和星期
+			typ:    dayIndex,
+			field:  "MON",
+			hasErr: true,
+		},
+		{
+			typ:    monthIndex,
+			field:  "MON",
+			hasErr: true,
+		},
+		{
+			typ:    weekIndex,
+			field:  "FRI-MON",
+			hasErr: true,
+		},
+		{ // 步长超出字段的范围
+			typ:    secondIndex,
+			field:  "*/60",
+			hasErr: true,
+		},
+		{
+			typ:    weekIndex,
+			field:  "*/100",
+			hasErr: true,
+		},
+		{
+			typ:    monthIndex,
+			field:  "*/12",
+			hasErr: true,
+		},
+		{ // 步长超出剩余的范围，只产生起始值
+			typ:   hourIndex,
+			field: "1-5/5",
+			vals:  pow2(1),
+		},
+		{
+			typ:   secondIndex,
+			field: "59/2",
+			vals:  pow2(59),
+		},
+		{
+			typ:   minuteIndex,
+			field: "50/10",
+			vals:  pow2(50),
+		},
+		{
+			typ:   monthIndex,
+			field: "*/11",
+			vals:  pow2(1, 12),
+		},
+		{ // 起始值超出范围
+			typ:    secondIndex,
+			field:  "60/2",
+			hasErr: true,
+		},
+		{
+			typ:   hourIndex,
+			field: "*/23",
+			vals:  pow2(0, 23),
+		},
+		{ // 步长格式错误
+			typ:    secondIndex,
+			field:  "1-5/a",
+			hasErr: true,
+		},
+		{
+			typ:    secondIndex,
+			field:  "1-5/",
+			hasErr: true,
+		},
+		{ // 步长产生重复的值
+			typ:    secondIndex,
+			field:  "*/20,10-50/10",
+			hasErr: true,
+		},
+		{ // * 不能与其它值组合
+			typ:    secondIndex,
+			field:  "*,5",
+			hasErr: true,
+		},
+		{ // 超过两个字符的数值也需要检测范围
+			typ:    secondIndex,
+			field:  "100",
+			hasErr: true,
+		},
+		{ // 起始值大于结束值
+			typ:    secondIndex,
+			field:  "5-1",
+			hasErr: true,
+		},
 		{ // 超出范围，月份没有 13
 			typ:    monthIndex,
 			field:  "1-13",
@@ -311,3 +589,80 @@ func TestBits_next(t *testing.T) {
 			Equal(c, item.c, "data[%d] 错误，实际返回:%v 期望值:%v", i, c, item.c)
 	}
 }
+
+func TestParseValues(t *testing.T) {
+	a := assert.New(t)
+
+	vals, err := ParseValues("*", 1, 3)
+	a.NotError(err).Equal(vals, []int{1, 2, 3})
+
+	vals, err = ParseValues("1-12/4,2", 1, 12)
+	a.NotError(err).Equal(vals, []int{1, 2, 5, 9})
+
+	vals, err = ParseValues("0,59", 0, 59)
+	a.NotError(err).Equal(vals, []int{0, 59})
+
+	vals, err = ParseValues("4", 1, 3)
+	a.Error(err).Nil(vals)
+
+	vals, err = ParseValues("L", 1, 3)
+	a.Error(err).Nil(vals)
+
+	vals, err = ParseValues("MON", 0, 7)
+	a.Error(err).Nil(vals)
+
+	vals, err = ParseValues("1", 0, 60)
+	a.Error(err).Nil(vals)
+
+	vals, err = ParseValues("1", 3, 1)
+	a.Error(err).Nil(vals)
+}
+
+func TestReplaceHash(t *testing.T) {
+	a := assert.New(t)
+
+	v, err := replaceHash(secondIndex, "1,2", "")
+	a.NotError(err).Equal(v, "1,2")
+
+	v, err = replaceHash(weekIndex, "MON,THU", "")
+	a.NotError(err).Equal(v, "MON,THU")
+
+	v, err = replaceHash(secondIndex, "H", "")
+	a.Error(err).Empty(v)
+
+	// 相同的键名返回相同的值
+	v1, err := replaceHash(minuteIndex, "H", "job1")
+	a.NotError(err)
+	v2, err := replaceHash(minuteIndex, "H", "job1")
+	a.NotError(err).Equal(v1, v2)
+
+	// 值在范围之内
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("job-%d", i)
+
+		v, err = replaceHash(dayIndex, "H", key)
+		a.NotError(err)
+		n, err := strconv.Atoi(v)
+		a.NotError(err).True(n >= 1 && n <= 28, n)
+
+		v, err = replaceHash(weekIndex, "H", key)
+		a.NotError(err)
+		n, err = strconv.Atoi(v)
+		a.NotError(err).True(n >= 0 && n <= 6, n)
+
+		v, err = replaceHash(minuteIndex, "H/15", key)
+		a.NotError(err)
+		vals, err := parseField(minuteIndex, v)
+		a.NotError(err)
+		a.Equal(bits.OnesCount64(uint64(vals)), 4, v)
+	}
+
+	v, err = replaceHash(secondIndex, "H/0", "job")
+	a.Error(err).Empty(v)
+
+	v, err = replaceHash(secondIndex, "H/60", "job")
+	a.Error(err).Empty(v)
+
+	v, err = replaceHash(secondIndex, "H/a", "job")
+	a.Error(err).Empty(v)
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+// 以 go test -run TestGolden -update 重新生成 testdata/golden.txt 中的执行时间
+var update = flag.Bool("update", false, "更新 testdata 中的 golden 文件")
+
+const (
+	goldenFile  = "testdata/golden.txt"
+	goldenCount = 10 // 每个用例记录的执行时间数量
+)
+
+// golden 文件中的单个用例
+//
+// 文件中每个用例由一个头部以及最多 goldenCount 行执行时间组成，用例之间以空行分隔，
+// 头部的格式为 expr | tz | anchor，anchor 为 tz 时区中的时间，格式为 2006-01-02T15:04:05，
+// 也可以是带时区偏移的 RFC3339 格式，用于指定夏令时结束时重复时间段中的时间点。
+// 以 # 开头的行为注释。
+type goldenCase struct {
+	header, expr string
+	anchor       time.Time
+	want         []string
+}
+
+func TestGolden(t *testing.T) {
+	a := assert.New(t)
+
+	data, err := ioutil.ReadFile(goldenFile)
+	a.NotError(err)
+	cases, comments, err := parseGolden(data)
+	a.NotError(err).NotEmpty(cases)
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(comments)
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		a.NotError(err, "%s 解析出错：%v", c.header, err)
+		if err != nil {
+			continue
+		}
+
+		got := make([]string, 0, goldenCount)
+		for last := c.anchor; len(got) < goldenCount; {
+			next := s.Next(last)
+			if next.IsZero() {
+				break
+			}
+			a.True(next.After(last), "%s 在 %s 之后返回了 %s", c.header, last, next)
+			got = append(got, next.Format(time.RFC3339))
+			last = next
+		}
+
+		if !*update {
+			a.Equal(got, c.want, "%s 出错，返回值：%v，期望值：%v", c.header, got, c.want)
+			continue
+		}
+
+		fmt.Fprintf(buf, "\n%s\n", c.header)
+		for _, line := range got {
+			fmt.Fprintln(buf, line)
+		}
+	}
+
+	if *update {
+		a.NotError(ioutil.WriteFile(goldenFile, buf.Bytes(), 0644))
+	}
+}
+
+// 分析 golden 文件的内容，comments 为文件开头的注释。
+func parseGolden(data []byte) (cases []*goldenCase, comments string, err error) {
+	var c *goldenCase
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "#"):
+			if len(cases) == 0 {
+				comments += line + "\n"
+			}
+		case strings.TrimSpace(line) == "":
+			c = nil
+		case c == nil:
+			if c, err = parseGoldenHeader(line); err != nil {
+				return nil, "", err
+			}
+			cases = append(cases, c)
+		default:
+			c.want = append(c.want, line)
+		}
+	}
+
+	return cases, comments, s.Err()
+}
+
+func parseGoldenHeader(line string) (*goldenCase, error) {
+	fs := strings.Split(line, " | ")
+	if len(fs) != 3 {
+		return nil, fmt.Errorf("无效的头部：%s", line)
+	}
+
+	loc, err := time.LoadLocation(fs[1])
+	if err != nil {
+		return nil, err
+	}
+
+	// 带时区偏移的 anchor 用于指定夏令时结束时重复时间段中的时间点
+	anchor, err := time.Parse(time.RFC3339, fs[2])
+	if err == nil {
+		anchor = anchor.In(loc)
+	} else if anchor, err = time.ParseInLocation("2006-01-02T15:04:05", fs[2], loc); err != nil {
+		return nil, err
+	}
+
+	return &goldenCase{
+		header: line,
+		expr:   fs[0],
+		anchor: anchor,
+		want:   make([]string, 0, goldenCount),
+	}, nil
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+type lazy struct {
+	spec string
+	opts []Option
+	once sync.Once
+
+	s   schedulers.Scheduler
+	err error
+}
+
+// Lazy 返回在第一次调用 Next 时才解析 spec 的调度器
+//
+// 适用于启动时需要加载大量表达式的场景，以第一次执行时稍慢的代价换取更快的启动速度。
+// Lazy 仅对 spec 作字段数量之类的简单检测，其它的错误在第一次调用 Next 时才会发现，
+// 此时 Next 返回零值，并可以通过返回对象的 Err() error 方法获取该错误。
+// spec 的格式与 Parse 相同。
+//
+// 以 @ 开头的指令不会延迟解析。
+func Lazy(spec string, opts ...Option) (schedulers.Scheduler, error) {
+	if spec == "" {
+		return nil, errors.New("参数 spec 不能为空")
+	}
+
+	if spec[0] == '@' && strings.IndexByte(spec, ';') < 0 {
+		return Parse(spec, opts...)
+	}
+
+	for _, item := range strings.Split(spec, ";") {
+		if err := checkLength(strings.TrimSpace(item)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &lazy{spec: spec, opts: opts}, nil
+}
+
+// 检测单个表达式的字段数量，指令只检测其是否为空。
+func checkLength(spec string) error {
+	_, rest, _, err := parseTZ(spec)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case rest == "":
+		return errors.New("参数 spec 不能为空")
+	case rest[0] == '@':
+		return nil
+	}
+
+	if n := len(strings.Fields(rest)); n != indexSize && n != indexSize+1 {
+		return errors.New("长度不正确")
+	}
+	return nil
+}
+
+func (l *lazy) Title() string {
+	return l.spec
+}
+
+func (l *lazy) String() string {
+	return l.spec
+}
+
+func (l *lazy) Next(last time.Time) time.Time {
+	l.once.Do(func() {
+		l.s, l.err = Parse(l.spec, l.opts...)
+	})
+
+	if l.err != nil {
+		return time.Time{}
+	}
+	return l.s.Next(last)
+}
+
+// Err 返回解析时的错误，在第一次调用 Next 之前始终返回 nil。
+func (l *lazy) Err() error {
+	return l.err
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var (
+	_ schedulers.Scheduler = &lazy{}
+	_ fmt.Stringer         = &lazy{}
+)
+
+func TestLazy(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := Lazy("")
+	a.Error(err).Nil(s)
+
+	s, err = Lazy("* * *")
+	a.Error(err).Nil(s)
+
+	// 指令不会延迟解析
+	s, err = Lazy("@not-exists")
+	a.Error(err).Nil(s)
+	s, err = Lazy("@daily")
+	a.NotError(err).NotNil(s)
+	_, ok := s.(*lazy)
+	a.False(ok)
+
+	s, err = Lazy("0 30 9 * * *")
+	a.NotError(err).NotNil(s)
+	l, ok := s.(*lazy)
+	a.True(ok).Nil(l.s)
+	a.Equal(s.Title(), "0 30 9 * * *")
+	a.Equal(s.Next(now), time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC))
+	a.NotNil(l.s).NotError(l.Err())
+
+	// 与 Parse 接受相同的格式
+	for _, spec := range []string{"0 0 3 * * 1-5; 0 0 12 * * 6", "CRON_TZ=UTC @daily", "@daily; CRON_TZ=UTC 0 0 9 * * *"} {
+		s, err = Lazy(spec)
+		a.NotError(err, "%s 出错 %s", spec, err).NotNil(s)
+		p, err := Parse(spec)
+		a.NotError(err).NotNil(p)
+		a.Equal(s.Next(now), p.Next(now))
+		a.NotError(s.(*lazy).Err())
+	}
+
+	s, err = Lazy("0 0 3 * * 1-5; * * *")
+	a.Error(err).Nil(s)
+	s, err = Lazy("0 0 3 * * 1-5;")
+	a.Error(err).Nil(s)
+	s, err = Lazy("CRON_TZ=UTC")
+	a.Error(err).Nil(s)
+
+	// 延迟发现的错误
+	s, err = Lazy("0 30 25 * * *")
+	a.NotError(err).NotNil(s)
+	l, ok = s.(*lazy)
+	a.True(ok).NotError(l.Err())
+	a.True(s.Next(now).IsZero())
+	a.Error(l.Err())
+}
@@ -2,96 +2,373 @@
 
 package cron
 
-import "time"
+import (
+	"math/bits"
+	"time"
+)
 
-type datetime struct {
-	year                  int
-	month                 time.Month
-	day                   int
-	weekday               time.Weekday
-	hour, minitue, second int
-}
+// 查找下一个执行时间时，最多往后查找的年数
+//
+// 超过此值依然未找到符合条件的日期，则认为该表达式已经不会再执行。
+const maxYears = 400
 
 func (c *cron) Next(last time.Time) time.Time {
-	dt := &datetime{}
-	dt.year, dt.month, dt.day = last.Date()
-	dt.weekday = last.Weekday()
-	dt.hour = last.Hour()
-	dt.minitue = last.Minute()
-	dt.second = last.Second()
+	// 时间部分的 any 表示保持 last 中的值不变
+	hours := c.data[hourIndex].expand(bounds[hourIndex], last.Hour())
+	minutes := c.data[minuteIndex].expand(bounds[minuteIndex], last.Minute())
+	seconds := c.data[secondIndex].expand(bounds[secondIndex], last.Second())
+
+	year, month, day := last.Date()
+	if c.matchDay(year, month, day) {
+		h, m, s := last.Hour(), last.Minute(), last.Second()
+		for {
+			var ok bool
+			if h, m, s, ok = nextClock(hours, minutes, seconds, h, m, s); !ok {
+				break
+			}
 
-	second, carry := c.data[secondIndex].next(dt.second, bounds[secondIndex], true)
-	minute, carry := c.data[minuteIndex].next(dt.minitue, bounds[minuteIndex], carry)
-	hour, carry := c.data[hourIndex].next(dt.hour, bounds[hourIndex], carry)
+			// 夏令时结束时，重复的时间段中 date 可能返回早于 last 的时间。
+			if next := dateAfter(year, month, day, h, m, s, last); next.After(last) {
+				return next
+			}
+		}
+	}
 
-	var year, month, day int
-	if c.data[weekIndex] != any && c.data[weekIndex] != step {
-		year, month, day = c.nextWeekDay(dt, carry)
-	} else {
-		year, month, day = c.nextMonthDay(dt, carry)
+	h, m, s := hours.min(), minutes.min(), seconds.min()
+	for end := year + maxYears; year <= end; {
+		if day++; day > getMonthDays(month, year) {
+			day = 1
+			if month++; month > time.December {
+				month = time.January
+				year++
+			}
+		}
+
+		if c.maxYear > 0 && year > c.maxYear { // 已经超过最大年份，不会再执行。
+			return time.Time{}
+		}
+
+		if !c.matchYear(year) { // 整年都不符合要求，直接跳到下一年。
+			month, day = time.December, 31
+			continue
+		}
+
+		if day == 1 && c.skipMonth(year, month) {
+			day = getMonthDays(month, year) // 整个月都不符合要求，直接跳到下个月。
+			continue
+		}
+
+		if c.matchDay(year, month, day) {
+			return date(year, month, day, h, m, s, last.Location())
+		}
 	}
 
-	return time.Date(year, time.Month(month), day, hour, minute, second, 0, last.Location())
+	return time.Time{}
 }
 
-func (c *cron) nextMonthDay(dt *datetime, carry bool) (year, month, day int) {
-	dayBounds := bounds[dayIndex]
-	dayBounds.max = getMonthDays(dt.month, dt.year) // 最大的天数根据月份不同而不同
+// Prev 实现 schedulers.PrevScheduler 接口
+func (c *cron) Prev(t time.Time) time.Time {
+	// 时间部分的 any 表示保持 t 中的值不变
+	hours := c.data[hourIndex].expand(bounds[hourIndex], t.Hour())
+	minutes := c.data[minuteIndex].expand(bounds[minuteIndex], t.Minute())
+	seconds := c.data[secondIndex].expand(bounds[secondIndex], t.Second())
 
-	day, carry = c.data[dayIndex].next(dt.day, dayBounds, carry)
-	month, carry = c.data[monthIndex].next(int(dt.month), bounds[monthIndex], carry)
-	year = dt.year
-	if carry {
-		year++
+	year, month, day := t.Date()
+	if c.matchDay(year, month, day) {
+		h, m, s, ok := prevClock(hours, minutes, seconds, t.Hour(), t.Minute(), t.Second())
+		if prev := date(year, month, day, h, m, s, t.Location()); ok && prev.Before(t) { // 夏令时的切换可能导致 prev 晚于 t
+			return prev
+		}
 	}
 
-	for { // 由于月份中的天数不固定，还得计算该天数是否存在于当前月分
-		days := getMonthDays(time.Month(month), year)
-		if day <= days { // 天数存在于当前月，则退出循环
-			return year, month, day
+	h, m, s := hours.max(), minutes.max(), seconds.max()
+	for end := year - maxYears; year >= end; {
+		if day--; day < 1 {
+			if month--; month < time.January {
+				month = time.December
+				year--
+			}
+			day = getMonthDays(month, year)
+		}
+
+		if c.years != nil && year < bounds[yearIndex].min { // 已经早于最小年份，之前不会有执行时间。
+			return time.Time{}
 		}
 
-		month, carry = c.data[monthIndex].next(month, bounds[monthIndex], true)
-		if carry {
-			year++
+		if !c.matchYear(year) { // 整年都不符合要求，直接跳到上一年。
+			month, day = time.January, 1
+			continue
 		}
+
+		if day == getMonthDays(month, year) && c.skipMonth(year, month) {
+			day = 1 // 整个月都不符合要求，直接跳到上个月。
+			continue
+		}
+
+		if c.matchDay(year, month, day) {
+			return date(year, month, day, h, m, s, t.Location())
+		}
+	}
+
+	return time.Time{}
+}
+
+// 返回 loc 时区中 year-month-day h:m:s 所表示的时间
+//
+// 与 time.Date 不同，如果该时间因为夏令时的切换而不存在，总是返回切换之后对应的时间，
+// 比如 02:00 调整为 03:00 时，02:30 会返回 03:30，而不是 01:30。
+func date(year int, month time.Month, day, h, m, s int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, h, m, s, 0, loc)
+
+	want := time.Date(year, month, day, h, m, s, 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	if d := want.Sub(got); d > 0 {
+		return t.Add(d)
 	}
+	return t
 }
 
-func (c *cron) nextWeekDay(dt *datetime, carry bool) (year, month, day int) {
-	// 计算 week day 在当前月份中的日期
-	wday, ca := c.data[weekIndex].next(int(dt.weekday), bounds[weekIndex], carry)
-	dur := wday - int(dt.weekday) // 相差的天数
-	if (dur < 0) || (ca && dur == 0) {
-		dur += 7
+// 与 date 相同，但在夏令时结束时重复的时间段中，优先返回晚于 last 的时间
+//
+// 比如 02:00 调整为 01:00 时，01:30 存在两个时间点，
+// last 为第二个 01:20 时，返回第二个 01:30，而不是早于 last 的第一个 01:30。
+func dateAfter(year int, month time.Month, day, h, m, s int, last time.Time) time.Time {
+	t := date(year, month, day, h, m, s, last.Location())
+	if t.After(last) {
+		return t
 	}
-	day = dur + dt.day // wday 在当前月对应的天数
-	year = dt.year
 
-	// 此处忽略返回的 c 参数。参数 carry 为 false，则肯定不会返回值为 true 的 carry
-	month, _ = c.data[monthIndex].next(int(dt.month), bounds[monthIndex], false)
-	if time.Month(month) != dt.month {
-		day = getMonthWeekDay(time.Weekday(wday), time.Month(month), year)
-	} else if days := getMonthDays(time.Month(month), year); day > days {
-		// 跨月份，还有可能跨年份
-		month, ca = c.data[monthIndex].next(month, bounds[monthIndex], true)
-		if ca {
-			year++
+	_, off := t.Zone()
+	_, lastOff := last.Zone()
+	if later := t.Add(time.Duration(off-lastOff) * time.Second); later.After(t) && later.Hour() == h && later.Minute() == m && later.Second() == s {
+		return later
+	}
+	return t
+}
+
+// 判断 year-month 整个月是否都不符合表达式中月份的要求
+func (c *cron) skipMonth(year int, month time.Month) bool {
+	return !c.data[monthIndex].match(int(month)) && !(c.leapDay == LeapDayMar1 && month == time.March && c.replaceLeapDay(year))
+}
+
+// 在平年中是否需要以其它日期代替 2 月 29 日
+func (c *cron) replaceLeapDay(year int) bool {
+	days := c.data[dayIndex]
+	return c.leapDay != LeapDaySkip &&
+		getMonthDays(time.February, year) == 28 &&
+		c.data[monthIndex].match(int(time.February)) &&
+		days != any && days != step && days.match(29)
+}
+
+// 判断 year-month-day 是否符合表达式中与日期相关的要求
+func (c *cron) matchDay(year int, month time.Month, day int) bool {
+	if !c.matchYear(year) {
+		return false
+	}
+
+	if c.replaceLeapDay(year) {
+		switch {
+		case c.leapDay == LeapDayFeb28 && month == time.February && day == 28,
+			c.leapDay == LeapDayMar1 && month == time.March && day == 1:
+			return true
 		}
-		day = getMonthWeekDay(time.Weekday(wday), time.Month(month), year)
 	}
 
-	// 同时设置了 day，需要比较两个值哪个更近
-	if c.data[dayIndex] != any && c.data[dayIndex] != step {
-		y, m, d := c.nextMonthDay(dt, carry)
-		if !(year < y || month < m || day < d) {
-			year = y
-			month = m
-			day = d
+	if !c.data[monthIndex].match(int(month)) {
+		return false
+	}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	if c.weekOfMonth != 0 && !c.weekOfMonth.match((day-1)/7+1) {
+		return false
+	}
+
+	if c.dayOfYear != nil && !c.dayOfYear[t.YearDay()] {
+		return false
+	}
+
+	days, weeks := c.data[dayIndex], c.data[weekIndex]
+	daySet := days != any && days != step
+	weekSet := (weeks != any && weeks != step) || c.nth != [7]fields{}
+
+	switch {
+	case daySet && weekSet && c.dayAndWeek:
+		return c.matchMonthDay(year, month, day) && c.matchWeekDay(t)
+	case daySet && weekSet: // 星期与日同时存在，默认以或的形式组合。
+		return c.matchMonthDay(year, month, day) || c.matchWeekDay(t)
+	case weekSet:
+		return c.matchWeekDay(t)
+	default:
+		return c.matchMonthDay(year, month, day)
+	}
+}
+
+// 判断 year 是否符合年份字段的要求
+func (c *cron) matchYear(year int) bool {
+	if c.years == nil {
+		return true
+	}
+
+	b := bounds[yearIndex]
+	return b.valid(year) && c.years[year-b.min]
+}
+
+// 判断 day 是否符合日字段的要求
+func (c *cron) matchMonthDay(year int, month time.Month, day int) bool {
+	days := c.data[dayIndex]
+	monthDays := getMonthDays(month, year)
+
+	if days&last != 0 && day == monthDays {
+		return true
+	}
+
+	if c.beforeLast != 0 && day < monthDays && c.beforeLast.match(monthDays-day) {
+		return true
+	}
+
+	if c.nearest != 0 {
+		if c.nearest&last != 0 && day == nearestWeekday(year, month, monthDays) {
+			return true
+		}
+
+		for n := 1; n <= monthDays; n++ {
+			if c.nearest.match(n) && day == nearestWeekday(year, month, n) {
+				return true
+			}
 		}
 	}
 
-	return year, month, day
+	return days.match(day)
+}
+
+// 判断 t 是否符合星期字段的要求
+func (c *cron) matchWeekDay(t time.Time) bool {
+	w := t.Weekday()
+	if c.nth[w]&last != 0 && t.Day()+7 > getMonthDays(t.Month(), t.Year()) { // 当月最后一个星期 w
+		return true
+	}
+	return c.data[weekIndex].match(int(w)) || c.nth[w].match((t.Day()-1)/7+1)
+}
+
+// 获取离 year-month-day 最近的工作日，不会跨越月份。
+//
+// day 必须是当月存在的日期。
+func nearestWeekday(year int, month time.Month, day int) int {
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == getMonthDays(month, year) {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
+
+// 获取同一天中大于 h:m:s 的最近时间
+//
+// hours、minutes 和 seconds 必须是经过 fields.expand 处理的值；
+// ok 为 false 表示当天已经没有符合要求的时间。
+func nextClock(hours, minutes, seconds fields, h, m, s int) (hour, minute, second int, ok bool) {
+	hour, carry := hours.next(h, bounds[hourIndex], false)
+	if carry {
+		return 0, 0, 0, false
+	}
+	if hour > h {
+		return hour, minutes.min(), seconds.min(), true
+	}
+
+	minute, carry = minutes.next(m, bounds[minuteIndex], false)
+	if !carry {
+		if minute > m {
+			return hour, minute, seconds.min(), true
+		}
+
+		if second, carry = seconds.next(s, bounds[secondIndex], true); !carry {
+			return hour, minute, second, true
+		}
+
+		if minute, carry = minutes.next(m, bounds[minuteIndex], true); !carry {
+			return hour, minute, seconds.min(), true
+		}
+	}
+
+	if hour, carry = hours.next(h, bounds[hourIndex], true); carry {
+		return 0, 0, 0, false
+	}
+	return hour, minutes.min(), seconds.min(), true
+}
+
+// 获取同一天中小于 h:m:s 的最近时间
+//
+// 参数要求与 nextClock 相同，ok 为 false 表示当天之前已经没有符合要求的时间。
+func prevClock(hours, minutes, seconds fields, h, m, s int) (hour, minute, second int, ok bool) {
+	for hour = h; hour >= 0; hour-- {
+		if !hours.match(hour) {
+			continue
+		}
+
+		minute = bounds[minuteIndex].max
+		if hour == h {
+			minute = m
+		}
+		for ; minute >= 0; minute-- {
+			if !minutes.match(minute) {
+				continue
+			}
+
+			second = bounds[secondIndex].max
+			if hour == h && minute == m {
+				second = s - 1
+			}
+			for ; second >= 0; second-- {
+				if seconds.match(second) {
+					return hour, minute, second, true
+				}
+			}
+		}
+	}
+
+	return 0, 0, 0, false
+}
+
+// 将 any 和 step 转换成普通的位集合
+//
+// any 转换成仅包含 curr 的集合，step 转换成包含 b 范围内所有值的集合。
+func (fs fields) expand(b bound, curr int) fields {
+	switch fs {
+	case any:
+		return 1 << uint64(curr)
+	case step:
+		var ret fields
+		for i := b.min; i <= b.max; i++ {
+			ret |= 1 << uint64(i)
+		}
+		return ret
+	default:
+		return fs
+	}
+}
+
+// 判断 v 是否符合 fs 的要求
+func (fs fields) match(v int) bool {
+	return fs == any || fs == step || (uint64(1)<<uint64(v))&uint64(fs) > 0
+}
+
+// 集合中的最小值，fs 不能是 any 或 step
+func (fs fields) min() int {
+	return bits.TrailingZeros64(uint64(fs))
+}
+
+// 集合中的最大值，fs 不能是 any 或 step
+func (fs fields) max() int {
+	return 63 - bits.LeadingZeros64(uint64(fs))
 }
 
 // 获取指定月份的天数
@@ -100,14 +377,3 @@ func getMonthDays(month time.Month, year int) int {
 	last := first.AddDate(0, 1, -1)
 	return last.Day()
 }
-
-// 获取指定 year-month 下第一个符合 weekday 对应的天数
-func getMonthWeekDay(weekday time.Weekday, month time.Month, year int) int {
-	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	weekday -= first.Weekday()
-	if weekday < 0 {
-		weekday += 7
-	}
-	last := first.AddDate(0, 0, int(weekday))
-	return last.Day()
-}
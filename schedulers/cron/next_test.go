@@ -4,10 +4,14 @@ package cron
 
 import (
 	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
 )
 
 func TestCron_Next(t *testing.T) {
@@ -168,6 +172,215 @@ func TestCron_Next(t *testing.T) {
 				"2020-03-31 03:22:01",
 			},
 		},
+
+		{
+			expr: "10-50/20 */30 * * * *",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-01-01 00:00:10",
+				"2019-01-01 00:00:30",
+				"2019-01-01 00:00:50",
+				"2019-01-01 00:30:10",
+			},
+		},
+
+		{ // 分钟变化时，秒数应该从最小值开始
+			expr: "0,30 5 * * * *",
+			times: []string{
+				"2019-01-01 00:04:10",
+				"2019-01-01 00:05:00",
+				"2019-01-01 00:05:30",
+				"2019-01-01 01:05:00",
+			},
+		},
+
+		{ // 只指定了星期，跨月份
+			expr: "* * * * * 1",
+			times: []string{
+				"2019-01-29 10:20:30",
+				"2019-02-04 10:20:30",
+				"2019-02-11 10:20:30",
+			},
+		},
+
+		{ // 每月最后一天
+			expr: "0 0 0 L * *",
+			times: []string{
+				"2019-01-10 10:20:30",
+				"2019-01-31 00:00:00",
+				"2019-02-28 00:00:00",
+				"2019-03-31 00:00:00",
+				"2019-04-30 00:00:00",
+			},
+		},
+
+		{ // 闰年的 2 月
+			expr: "0 0 0 L 2 *",
+			times: []string{
+				"2019-03-01 00:00:00",
+				"2020-02-29 00:00:00",
+				"2021-02-28 00:00:00",
+			},
+		},
+
+		{ // L 与其它值组合
+			expr: "0 0 0 15,L * *",
+			times: []string{
+				"2019-01-10 10:20:30",
+				"2019-01-15 00:00:00",
+				"2019-01-31 00:00:00",
+				"2019-02-15 00:00:00",
+				"2019-02-28 00:00:00",
+			},
+		},
+
+		{ // L 与星期组合，以或的形式组合。
+			expr: "0 0 0 L * 1",
+			times: []string{
+				"2019-01-26 10:20:30",
+				"2019-01-28 00:00:00", // 周一
+				"2019-01-31 00:00:00",
+				"2019-02-04 00:00:00", // 周一
+			},
+		},
+
+		{ // 离 15 日最近的工作日
+			expr: "0 0 0 15W * *",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-01-15 00:00:00", // 周二
+				"2019-02-15 00:00:00", // 周五
+				"2019-03-15 00:00:00", // 周五
+				"2019-04-15 00:00:00", // 周一
+				"2019-05-15 00:00:00", // 周三
+				"2019-06-14 00:00:00", // 15 日为周六
+				"2019-07-15 00:00:00", // 周一
+				"2019-08-15 00:00:00", // 周四
+				"2019-09-16 00:00:00", // 15 日为周日
+			},
+		},
+
+		{ // 不跨越月份
+			expr: "0 0 0 1W,30W * *",
+			times: []string{
+				"2019-06-01 00:00:00",
+				"2019-06-03 00:00:00", // 1 日为周六
+				"2019-06-28 00:00:00", // 30 日为周日
+				"2019-07-01 00:00:00",
+			},
+		},
+
+		{ // 每月最后一个工作日
+			expr: "0 0 18 LW * *",
+			times: []string{
+				"2019-06-01 00:00:00",
+				"2019-06-28 18:00:00", // 30 日为周日
+				"2019-07-31 18:00:00",
+				"2019-08-30 18:00:00", // 31 日为周六
+			},
+		},
+
+		{ // W 与普通的值组合
+			expr: "0 0 0 1,15W * *",
+			times: []string{
+				"2019-06-01 00:00:00",
+				"2019-06-14 00:00:00",
+				"2019-07-01 00:00:00",
+				"2019-07-15 00:00:00",
+			},
+		},
+
+		{ // 每月第三个周五
+			expr: "0 0 9 * * 5#3",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-01-18 09:00:00",
+				"2019-02-15 09:00:00",
+				"2019-03-15 09:00:00",
+			},
+		},
+
+		{ // 每月最后一天之前的第 3 天
+			expr: "0 0 0 L-3 * *",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-01-28 00:00:00",
+				"2019-02-25 00:00:00",
+				"2019-03-28 00:00:00",
+				"2019-04-27 00:00:00",
+			},
+		},
+
+		{ // 只有 31 天的月份才有 L-30
+			expr: "0 0 0 L-30 * *",
+			times: []string{
+				"2020-01-01 00:00:00",
+				"2020-03-01 00:00:00",
+				"2020-05-01 00:00:00",
+				"2020-07-01 00:00:00",
+			},
+		},
+
+		{ // 每月最后一个周五
+			expr: "0 0 9 * * 5L",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-01-25 09:00:00",
+				"2019-02-22 09:00:00",
+				"2019-03-29 09:00:00",
+				"2019-04-26 09:00:00",
+				"2019-05-31 09:00:00",
+			},
+		},
+
+		{ // 第五个星期并不是每月都存在
+			expr: "0 0 0 * * 0#5",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-03-31 00:00:00",
+				"2019-06-30 00:00:00",
+				"2019-09-29 00:00:00",
+			},
+		},
+
+		{ // # 与普通的星期组合
+			expr: "0 0 0 * * 1#1,6",
+			times: []string{
+				"2019-01-01 00:00:00",
+				"2019-01-05 00:00:00", // 周六
+				"2019-01-07 00:00:00", // 第一个周一
+				"2019-01-12 00:00:00", // 周六
+				"2019-01-19 00:00:00",
+			},
+		},
+
+		{ // 指定年份
+			expr: "0 0 0 1 1 * 2026-2028",
+			times: []string{
+				"2019-06-01 00:00:00",
+				"2026-01-01 00:00:00",
+				"2027-01-01 00:00:00",
+				"2028-01-01 00:00:00",
+			},
+		},
+
+		{ // 当天符合要求
+			expr: "0 0 12 * * * 2020",
+			times: []string{
+				"2020-06-01 00:00:00",
+				"2020-06-01 12:00:00",
+				"2020-06-02 12:00:00",
+			},
+		},
+
+		{ // 只指定了日，跨月份
+			expr: "* * * 5 * *",
+			times: []string{
+				"2019-01-10 10:20:30",
+				"2019-02-05 10:20:30",
+				"2019-03-05 10:20:30",
+			},
+		},
 	}
 
 	for i, t := range data {
@@ -196,6 +409,389 @@ func TestCron_Next(t *testing.T) {
 	}
 }
 
+func TestCron_Next_boundary(t *testing.T) {
+	a := assert.New(t)
+
+	data := []struct {
+		expr       string
+		last, want time.Time
+	}{
+		{ // 跨年
+			expr: "59 59 23 31 12 *",
+			last: time.Date(2019, 12, 31, 23, 59, 59, 0, time.UTC),
+			want: time.Date(2020, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			expr: "59 59 23 31 12 *",
+			last: time.Date(2019, 12, 31, 23, 59, 58, 0, time.UTC),
+			want: time.Date(2019, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{ // 跨月
+			expr: "0 0 0 * * *",
+			last: time.Date(2019, 1, 31, 23, 59, 59, 0, time.UTC),
+			want: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{ // 零点之前的最后一个时间
+			expr: "*/10 59 23 * * *",
+			last: time.Date(2019, 2, 28, 23, 59, 50, 0, time.UTC),
+			want: time.Date(2019, 3, 1, 23, 59, 0, 0, time.UTC),
+		},
+		{ // 秒进位到分，分进位到时
+			expr: "0,30 0,30 * * * *",
+			last: time.Date(2019, 12, 31, 23, 30, 30, 0, time.UTC),
+			want: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{ // 小时进位到日，且跳过不存在的日期
+			expr: "0 0 22-23 30 * *",
+			last: time.Date(2020, 1, 30, 23, 0, 0, 0, time.UTC),
+			want: time.Date(2020, 3, 30, 22, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, item := range data {
+		s, err := Parse(item.expr)
+		a.NotError(err).NotNil(s)
+		next := s.Next(item.last)
+		a.Equal(next, item.want, "%s 出错，返回值：%s，期望值：%s", item.expr, next, item.want)
+	}
+}
+
+// 与逐秒查找的结果进行比较
+//
+// 表达式的第一个字段不能为 *，否则其语义为保持 last 中的值不变，无法简单地逐秒比较。
+func TestCron_Next_bruteForce(t *testing.T) {
+	a := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+
+	exprs := []string{
+		"59 59 23 31 12 *",
+		"0 0 0 29 2 *",
+		"0 0 0 31 * *",
+		"0 30 2 * * 0",
+		"*/15 0 0 1 * *",
+		"0 */7 9-17 * * 1-5",
+		"30 5 4 13 * 5",
+		"0 0 12 1,15 1,6,12 *",
+		"10-50/20 59 23 * 2 *",
+		"0 0 0 * * 6,0",
+	}
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for _, expr := range exprs {
+		s, err := Parse(expr)
+		a.NotError(err).NotNil(s)
+
+		vals, err := Fields(expr)
+		a.NotError(err)
+		fs := strings.Fields(expr)
+		m := &bruteMatcher{vals: vals, dayAll: fs[dayIndex] == "*", weekAll: fs[weekIndex] == "*"}
+
+		for i := 0; i < 20; i++ {
+			last := time.Unix(start+r.Int63n(5*365*24*3600), 0).UTC()
+			want := m.next(last)
+			next := s.Next(last)
+			a.Equal(next, want, "%s 在 %s 出错，返回值：%s，期望值：%s", expr, last, next, want)
+		}
+	}
+}
+
+type bruteMatcher struct {
+	vals            [indexSize][]uint8
+	dayAll, weekAll bool
+}
+
+func (m *bruteMatcher) has(index, v int) bool {
+	for _, vv := range m.vals[index] {
+		if int(vv) == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *bruteMatcher) matchDate(t time.Time) bool {
+	if !m.has(monthIndex, int(t.Month())) {
+		return false
+	}
+
+	day, week := m.has(dayIndex, t.Day()), m.has(weekIndex, int(t.Weekday()))
+	switch {
+	case !m.dayAll && !m.weekAll:
+		return day || week
+	case !m.weekAll:
+		return week
+	default:
+		return day
+	}
+}
+
+// 从 last 之后逐秒查找第一个符合要求的时间，不符合要求的日期、小时和分钟整体跳过。
+func (m *bruteMatcher) next(last time.Time) time.Time {
+	t := last.Add(time.Second)
+	for end := last.AddDate(10, 0, 0); t.Before(end); {
+		year, month, day := t.Date()
+		switch {
+		case !m.matchDate(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+		case !m.has(hourIndex, t.Hour()):
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, t.Location())
+		case !m.has(minuteIndex, t.Minute()):
+			t = time.Date(year, month, day, t.Hour(), t.Minute()+1, 0, 0, t.Location())
+		case !m.has(secondIndex, t.Second()):
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func TestCron_Prev(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("0 30 9 * * 1-5")
+	a.NotError(err).NotNil(s)
+	p := s.(schedulers.PrevScheduler)
+
+	// 2020-06-08 为周一
+	prev := p.Prev(time.Date(2020, 6, 8, 9, 30, 0, 0, time.UTC))
+	a.Equal(prev, time.Date(2020, 6, 5, 9, 30, 0, 0, time.UTC))
+	prev = p.Prev(time.Date(2020, 6, 8, 9, 30, 1, 0, time.UTC))
+	a.Equal(prev, time.Date(2020, 6, 8, 9, 30, 0, 0, time.UTC))
+
+	// 继承时区
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	prev = p.Prev(time.Date(2020, 6, 9, 0, 0, 0, 0, loc))
+	a.Equal(prev, time.Date(2020, 6, 8, 9, 30, 0, 0, loc))
+
+	s, err = Parse("0 0 0 29 2 *")
+	a.NotError(err).NotNil(s)
+	prev = s.(schedulers.PrevScheduler).Prev(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(prev, time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC))
+
+	// 早于年份字段的所有值
+	s, err = Parse("0 0 0 1 1 * 2026")
+	a.NotError(err).NotNil(s)
+	prev = s.(schedulers.PrevScheduler).Prev(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.True(prev.IsZero())
+	prev = s.(schedulers.PrevScheduler).Prev(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(prev, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// 与 Next 互逆
+	r := rand.New(rand.NewSource(1))
+	exprs := []string{
+		"59 59 23 31 12 *",
+		"0 0 0 31 * *",
+		"*/15 0 0 1 * *",
+		"0 */7 9-17 * * 1-5",
+		"10-50/20 59 23 * 2 *",
+		"0 0 0 L * *",
+		"0 0 0 15W * *",
+		"0 0 0 * * 5#3",
+		"0 0 0 * * 0L",
+		"0 0 0 L-1,L-15 * *",
+	}
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for _, expr := range exprs {
+		s, err := Parse(expr)
+		a.NotError(err).NotNil(s)
+		p := s.(schedulers.PrevScheduler)
+
+		for i := 0; i < 20; i++ {
+			last := time.Unix(start+r.Int63n(5*365*24*3600), 0).UTC()
+			next := s.Next(last)
+			prev := p.Prev(next)
+			a.False(prev.After(last), "%s 在 %s 出错，Prev 返回值：%s", expr, last, prev)
+			a.Equal(s.Next(prev), next, "%s 在 %s 出错，Prev 返回值：%s", expr, last, prev)
+		}
+	}
+}
+
+func TestCron_Next_dst(t *testing.T) {
+	a := assert.New(t)
+	loc, err := time.LoadLocation("America/New_York")
+	a.NotError(err).NotNil(loc)
+
+	// 2021-03-14 02:00 调整为 03:00，02:30 并不存在。
+	s, err := Parse("0 30 2 * * *")
+	a.NotError(err).NotNil(s)
+	next := s.Next(time.Date(2021, 3, 13, 12, 0, 0, 0, loc))
+	a.Equal(next, time.Date(2021, 3, 14, 3, 30, 0, 0, loc))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2021, 3, 15, 2, 30, 0, 0, loc))
+
+	prev := s.(schedulers.PrevScheduler).Prev(time.Date(2021, 3, 14, 3, 0, 0, 0, loc))
+	a.Equal(prev, time.Date(2021, 3, 13, 2, 30, 0, 0, loc))
+
+	// 2021-11-07 02:00 调整为 01:00，01:30 出现两次，仅执行一次。
+	s, err = Parse("0 30 1 * * *")
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2021, 11, 7, 0, 0, 0, 0, loc))
+	a.Equal(next.Hour(), 1).Equal(next.Minute(), 30)
+	next = s.Next(next)
+	a.Equal(next, time.Date(2021, 11, 8, 1, 30, 0, 0, loc))
+
+	// 在重复时间段的第二个 01:20，返回值不能早于 last。
+	s, err = Parse("0 */15 * * * *")
+	a.NotError(err).NotNil(s)
+	last := time.Date(2021, 11, 7, 1, 20, 0, 0, loc).Add(time.Hour)
+	_, off := last.Zone()
+	a.Equal(off, -5*60*60)
+	next = s.Next(last)
+	a.True(next.After(last)).Equal(next, last.Add(10*time.Minute))
+}
+
+func TestCron_Next_leapDay(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	collect := func(spec string, opts ...Option) []time.Time {
+		s, err := Parse(spec, opts...)
+		a.NotError(err).NotNil(s)
+
+		ret := make([]time.Time, 0, 6)
+		for next := start; len(ret) < cap(ret); {
+			next = s.Next(next)
+			ret = append(ret, next)
+		}
+		return ret
+	}
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	a.Equal(collect("0 0 0 29 2 *"), []time.Time{
+		date(2020, 2, 29), date(2024, 2, 29), date(2028, 2, 29),
+		date(2032, 2, 29), date(2036, 2, 29), date(2040, 2, 29),
+	})
+
+	a.Equal(collect("0 0 0 29 2 *", LeapDay(LeapDayFeb28)), []time.Time{
+		date(2019, 2, 28), date(2020, 2, 29), date(2021, 2, 28),
+		date(2022, 2, 28), date(2023, 2, 28), date(2024, 2, 29),
+	})
+
+	a.Equal(collect("0 0 0 29 2 *", LeapDay(LeapDayMar1)), []time.Time{
+		date(2019, 3, 1), date(2020, 2, 29), date(2021, 3, 1),
+		date(2022, 3, 1), date(2023, 3, 1), date(2024, 2, 29),
+	})
+
+	// 1900 和 2100 年不是闰年
+	s, err := Parse("0 0 0 29 2 *", LeapDay(LeapDayFeb28))
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(date(2100, 1, 1)), date(2100, 2, 28))
+
+	// 3 月 1 日本身也符合要求时，只执行一次。
+	a.Equal(collect("0 0 0 1,29 2,3 *", LeapDay(LeapDayMar1)), []time.Time{
+		date(2019, 2, 1), date(2019, 3, 1), date(2019, 3, 29),
+		date(2020, 2, 1), date(2020, 2, 29), date(2020, 3, 1),
+	})
+
+	// 日字段未明确包含 29 日时不受影响
+	a.Equal(collect("0 0 0 * 2 *", LeapDay(LeapDayMar1))[0:2], []time.Time{
+		date(2019, 2, 1), date(2019, 2, 2),
+	})
+
+	s, err = Parse("0 0 0 29 2 *", LeapDay(LeapDayMar1+1))
+	a.Error(err).Nil(s)
+
+	// 闰秒
+	s, err = Parse("60 0 0 * * *")
+	a.Error(err).Nil(s)
+}
+
+func TestCron_Next_year(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("0 0 0 1 1 * 2026,2028")
+	a.NotError(err).NotNil(s)
+	next := s.Next(time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.True(next.IsZero())
+
+	// 当天已经超过最大年份
+	s, err = Parse("0 0 12 * * * 2020")
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2020, 12, 31, 12, 0, 0, 0, time.UTC))
+	a.True(next.IsZero())
+	next = s.Next(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.True(next.IsZero())
+}
+
+func TestCron_Next_options(t *testing.T) {
+	a := assert.New(t)
+
+	// 每月第二周的周一
+	s, err := Parse("0 0 0 * * 1", WeekOfMonth(2))
+	a.NotError(err).NotNil(s)
+	next := s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2019, 1, 14, 0, 0, 0, 0, time.UTC))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2019, 2, 11, 0, 0, 0, 0, time.UTC))
+
+	// 每年的第 100 天
+	s, err = Parse("0 0 0 * * *", DayOfYear(100))
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2019, 4, 10, 0, 0, 0, 0, time.UTC))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2020, 4, 9, 0, 0, 0, 0, time.UTC)) // 闰年
+
+	// 仅闰年存在的第 366 天
+	s, err = Parse("0 0 0 * * *", DayOfYear(366))
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	// 13 日且为周五
+	s, err = Parse("0 0 0 13 * 5", DayAndWeek())
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2019, 9, 13, 0, 0, 0, 0, time.UTC))
+	next = s.Next(next)
+	a.Equal(next, time.Date(2019, 12, 13, 0, 0, 0, 0, time.UTC))
+
+	// 默认为或
+	s, err = Parse("0 0 0 13 * 5")
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2019, 1, 4, 0, 0, 0, 0, time.UTC))
+
+	// 星期为 * 时不受影响
+	s, err = Parse("0 0 0 13 * *", DayAndWeek())
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2019, 1, 13, 0, 0, 0, 0, time.UTC))
+
+	// 最后一个工作日且为周五
+	s, err = Parse("0 0 0 LW * 5", DayAndWeek())
+	a.NotError(err).NotNil(s)
+	next = s.Next(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2019, 3, 29, 0, 0, 0, 0, time.UTC))
+
+	s, err = Parse("0 0 0 * * *", WeekOfMonth(6))
+	a.Error(err).Nil(s)
+
+	s, err = Parse("0 0 0 * * *", DayOfYear())
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@reboot", DayOfYear(1))
+	a.Error(err).Nil(s)
+
+	s, err = Parse("@month-end", DayOfYear(1))
+	a.Error(err).Nil(s)
+
+	// 指令会忽略 Hash
+	for _, spec := range []string{"@reboot", "@every 1h", "@month-end", "CRON_TZ=Asia/Ho_Chi_Minh @every 1h"} {
+		s, err = Parse(spec, Hash("job"))
+		a.NotError(err, "%s 出错 %s", spec, err).NotNil(s)
+
+		s, err = Parse(spec, Hash("job"), DayAndWeek())
+		a.Error(err).Nil(s)
+	}
+	s, err = Parse("@reboot", Hash(""))
+	a.Error(err).Nil(s)
+}
+
 func TestGetMonthDays(t *testing.T) {
 	a := assert.New(t)
 
@@ -240,55 +836,3 @@ func TestGetMonthDays(t *testing.T) {
 		a.Equal(v, getMonthDays(time.Month(k), 2020))
 	}
 }
-
-func TestGetMonthWeekDay(t *testing.T) {
-	a := assert.New(t)
-
-	type test struct {
-		// 输入值
-		year    int
-		month   time.Month
-		weekday time.Weekday
-
-		// 返回值
-		day uint8
-	}
-
-	data := []*test{
-		{
-			year:    2019,
-			month:   time.May,
-			weekday: time.Wednesday,
-			day:     1,
-		},
-		{
-			year:    2019,
-			month:   time.May,
-			weekday: time.Saturday,
-			day:     4,
-		},
-		{
-			year:    2019,
-			month:   time.May,
-			weekday: time.Sunday,
-			day:     5,
-		},
-		{
-			year:    2020,
-			month:   time.February,
-			weekday: time.Saturday,
-			day:     1,
-		},
-		{
-			year:    2020,
-			month:   time.February,
-			weekday: time.Tuesday,
-			day:     4,
-		},
-	}
-
-	for index, item := range data {
-		day := getMonthWeekDay(item.weekday, item.month, item.year)
-		a.Equal(day, item.day, "%d 出错，返回值：%d，期望值：%d", index, day, item.day)
-	}
-}
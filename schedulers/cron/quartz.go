@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// ParseQuartz 解析 Quartz 格式的 cron 表达式
+//
+// 与 Parse 的区别在于：
+//  - 星期字段的数值从 1 开始，1 表示周日，7 表示周六，单独的 L 表示周六；
+//  - 日和星期字段必须有且只有一个为 ?；
+//  - 不支持 @ 开头的指令以及 CRON_TZ= 前缀；
+//  - 秒、分和小时字段中的 * 表示该字段的所有值，而不是保持 last 中的值，
+//    比如 * 30 9 ? * * 表示在 09:30:00 至 09:30:59 之间每秒执行一次。
+// 其它如 L、W、#、可选的年份字段等语法与 Parse 相同，opts 也与 Parse 相同。
+// 返回调度器的 Title 为 spec 本身，而 String 依然返回 Parse 格式的表达式。
+func ParseQuartz(spec string, opts ...Option) (schedulers.Scheduler, error) {
+	fs := strings.Fields(spec)
+	if len(fs) != indexSize && len(fs) != indexSize+1 {
+		return nil, errors.New("长度不正确")
+	}
+
+	if (fs[dayIndex] == "?") == (fs[weekIndex] == "?") {
+		return nil, errors.New("日和星期必须有且只有一个为 ?")
+	}
+
+	week, err := quartzWeekField(fs[weekIndex])
+	if err != nil {
+		return nil, &FieldError{
+			Index: weekIndex,
+			Field: fieldNames[weekIndex],
+			Input: fs[weekIndex],
+			Min:   1,
+			Max:   7,
+			Err:   err,
+		}
+	}
+	input := fs[weekIndex]
+	fs[weekIndex] = week
+
+	// 与 FromRobfig 相同，时间字段中的 * 需要转换成 */1 才表示所有值。
+	for i := secondIndex; i <= hourIndex; i++ {
+		if fs[i] == "*" {
+			fs[i] = "*/1"
+		}
+	}
+
+	s, err := Parse(strings.Join(fs, " "), opts...)
+	if err != nil {
+		var ferr *FieldError
+		if errors.As(err, &ferr) && ferr.Index == weekIndex { // 还原成用户的输入
+			ferr.Input = input
+			if b := bounds[weekIndex]; ferr.Min == b.min && ferr.Max == b.max {
+				ferr.Min, ferr.Max = 1, 7
+			}
+		}
+		return nil, err
+	}
+
+	if c, ok := s.(*cron); ok {
+		c.title = spec
+	}
+	return s, nil
+}
+
+// 将 Quartz 星期字段中的数值转换成 Parse 的格式
+//
+// 仅转换表示星期的数值，# 之后的序号以及 / 之后的步长保持不变。
+func quartzWeekField(field string) (string, error) {
+	if field == "*" || field == "?" {
+		return field, nil
+	}
+
+	fs := strings.Split(field, ",")
+	for i, v := range fs {
+		var suffix string
+		switch index := strings.IndexAny(v, "#/"); {
+		case v == "L": // 单独的 L 表示周六
+			fs[i] = "6"
+			continue
+		case index >= 0:
+			v, suffix = v[:index], v[index:]
+		case len(v) > 1 && v[len(v)-1] == 'L':
+			v, suffix = v[:len(v)-1], "L"
+		}
+
+		vals := strings.Split(v, "-")
+		for j, val := range vals {
+			if val == "*" {
+				continue
+			}
+
+			n, err := strconv.Atoi(val)
+			if err != nil { // 英文名称与 Parse 相同
+				continue
+			}
+			if n < 1 || n > 7 {
+				return "", fmt.Errorf("值 %d %w：[1,7]", n, ErrOutOfRange)
+			}
+			vals[j] = strconv.Itoa(n - 1)
+		}
+		if len(vals) == 1 && vals[0] != "*" && strings.HasPrefix(suffix, "/") {
+			// Parse 中 n/step 的范围包含表示周日的 7，且步长不能超出整个字段的范围，
+			// 而 Quartz 中步长超出剩余的范围时仅表示 n 本身。
+			n, err1 := parseValue(weekIndex, vals[0])
+			step, err2 := strconv.Atoi(suffix[1:])
+			if err1 == nil && err2 == nil && step > 6-n {
+				suffix = ""
+			} else {
+				vals = append(vals, "6")
+			}
+		}
+		fs[i] = strings.Join(vals, "-") + suffix
+	}
+
+	return strings.Join(fs, ","), nil
+}
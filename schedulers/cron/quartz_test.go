@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestParseQuartz(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC) // 周二
+
+	data := []*struct {
+		spec string
+		next string
+	}{
+		{spec: "0 0 12 ? * 1", next: "2019-01-06 12:00:00"},       // 周日
+		{spec: "0 0 12 ? * 7", next: "2019-01-05 12:00:00"},       // 周六
+		{spec: "0 0 12 ? * L", next: "2019-01-05 12:00:00"},       // 周六
+		{spec: "0 0 12 ? * 2-6", next: "2019-01-01 12:00:00"},     // 周一至周五
+		{spec: "0 0 12 ? * MON-FRI", next: "2019-01-01 12:00:00"}, // 名称与 Parse 相同
+		{spec: "0 0 12 ? * 6#3", next: "2019-01-18 12:00:00"},     // 第三个周五
+		{spec: "0 0 12 ? * 6L", next: "2019-01-25 12:00:00"},      // 最后一个周五
+		{spec: "0 0 12 ? * 4/2", next: "2019-01-02 12:00:00"},     // 周三、周五
+		{spec: "0 0 12 ? * 1,7", next: "2019-01-05 12:00:00"},     // 周末
+		{spec: "0 15 10 L * ?", next: "2019-01-31 10:15:00"},      // 每月最后一天
+		{spec: "0 15 10 15W * ?", next: "2019-01-15 10:15:00"},    // 离 15 日最近的工作日
+		{spec: "0 0 0 1 1 ? 2021", next: "2021-01-01 00:00:00"},   // 年份
+		{spec: "0 0/5 14 * * ?", next: "2019-01-01 14:00:00"},     // 每 5 分钟
+		{spec: "0 0 12 ? JAN-MAR 2", next: "2019-01-07 12:00:00"}, // 周一
+	}
+	for _, item := range data {
+		s, err := ParseQuartz(item.spec)
+		a.NotError(err, "%s 解析出错：%v", item.spec, err).NotNil(s)
+		a.Equal(s.Title(), item.spec)
+
+		want, err := time.ParseInLocation("2006-01-02 15:04:05", item.next, time.UTC)
+		a.NotError(err)
+		a.Equal(s.Next(now), want, "%s 出错，返回值：%s，期望值：%s", item.spec, s.Next(now), want)
+	}
+
+	// 时间字段的 * 表示每一个值
+	s, err := ParseQuartz("* * * ? * *")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.Add(time.Second))
+
+	s, err = ParseQuartz("* 30 9 ? * *")
+	a.NotError(err).NotNil(s)
+	next := s.Next(now)
+	a.Equal(next, time.Date(2019, 1, 1, 9, 30, 0, 0, time.UTC))
+	for i := 1; i < 60; i++ {
+		next = s.Next(next)
+		a.Equal(next, time.Date(2019, 1, 1, 9, 30, i, 0, time.UTC))
+	}
+	a.Equal(s.Next(next), time.Date(2019, 1, 2, 9, 30, 0, 0, time.UTC))
+
+	s, err = ParseQuartz("0 0 12 ? * 6/2")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.(*cron).String(), "0 0 12 * * 5")
+
+	// 日和星期必须有且只有一个 ?
+	for _, spec := range []string{"0 0 12 * * 1", "0 0 12 ? * ?", "0 0 12 1 * 1", "0 0 12 * * *"} {
+		s, err = ParseQuartz(spec)
+		a.Error(err, "%s 未返回错误", spec).Nil(s)
+	}
+
+	var ferr *FieldError
+	for _, spec := range []string{"0 0 12 ? * 0", "0 0 12 ? * 8", "0 0 12 ? * 1-8"} {
+		s, err = ParseQuartz(spec)
+		a.Error(err, "%s 未返回错误", spec).Nil(s)
+		a.True(errors.As(err, &ferr), spec)
+		a.Equal(ferr.Index, weekIndex).Equal(ferr.Min, 1).Equal(ferr.Max, 7)
+	}
+
+	// # 之后的 n 有自己的取值范围
+	s, err = ParseQuartz("0 0 12 ? * 2#6")
+	a.Error(err).Nil(s)
+	a.True(errors.As(err, &ferr))
+	a.Equal(ferr.Index, weekIndex).Equal(ferr.Input, "2#6").Equal(ferr.Min, 1).Equal(ferr.Max, 5)
+
+	s, err = ParseQuartz("0 0 12 ? *")
+	a.Error(err).Nil(s)
+
+	s, err = ParseQuartz("@daily")
+	a.Error(err).Nil(s)
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import "time"
+
+// @reboot 指令对应的调度器
+//
+// 第一次调用 Next 时返回 last，即立即执行，之后都返回零值。
+type reboot struct {
+	used bool
+}
+
+func (r *reboot) Next(last time.Time) time.Time {
+	if r.used {
+		return time.Time{}
+	}
+	r.used = true
+	return last
+}
+
+// Stateful 实现 schedulers.Stateful 接口
+func (r *reboot) Stateful() bool {
+	return !r.used
+}
+
+func (r *reboot) Title() string {
+	return "@reboot"
+}
+
+func (r *reboot) String() string {
+	return r.Title()
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var (
+	_ schedulers.Scheduler = &reboot{}
+	_ schedulers.Stateful  = &reboot{}
+	_ fmt.Stringer         = &reboot{}
+)
+
+func TestReboot(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := Parse("@reboot")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "@reboot")
+
+	// UTC 时区下也能正常执行
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// 预览不会消耗掉启动时的那一次执行
+	a.True(schedulers.IsStateful(s)).Nil(schedulers.NextN(s, now, 3))
+
+	a.Equal(s.Next(now), now)
+	a.False(schedulers.IsStateful(s))
+	a.True(s.Next(now).IsZero())
+	a.True(s.Next(now.Add(time.Hour)).IsZero())
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"strings"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// FromRobfig 解析 github.com/robfig/cron 格式的表达式
+//
+// 兼容 robfig/cron 的 ParseStandard 以及 WithSeconds 两种格式：
+// 5 个字段时与 ParseStandard 相同；6 个字段时与 Parse 相同。
+// @every、@hourly 等指令以及 CRON_TZ= 前缀的含义也都与 robfig/cron 相同。
+//
+// 与 Parse 不同，秒、分和小时字段中的 * 表示该字段的所有值，而不是保持 last 中的值，
+// 比如 * 30 9 * * * 表示在 09:30:00 至 09:30:59 之间每秒执行一次。
+// 转换之后的 * 以 */1 表示，所以 Title 中的 * 也会变成 */1。
+func FromRobfig(spec string) (schedulers.Scheduler, error) {
+	loc, rest, found, err := parseTZ(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(rest, "@") {
+		return Parse(spec)
+	}
+
+	fs := strings.Fields(rest)
+	if n := len(fs); n == indexSize-1 || n == indexSize {
+		for i := 0; i < n-3; i++ { // 日、月和星期之前的都是时间字段
+			if fs[i] == "*" {
+				fs[i] = "*/1"
+			}
+		}
+	}
+
+	rest = strings.Join(fs, " ")
+	if found {
+		rest = "CRON_TZ=" + loc.String() + " " + rest
+	}
+
+	if len(fs) == indexSize-1 {
+		return ParseStandard(rest)
+	}
+	return Parse(rest)
+}
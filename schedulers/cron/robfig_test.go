@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+func TestFromRobfig(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := FromRobfig("30 9 * * 1-5")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "0 30 9 * * 1-5")
+	a.Equal(s.Next(now), time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC))
+
+	s, err = FromRobfig("15 30 9 * * *")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), time.Date(2020, 1, 1, 9, 30, 15, 0, time.UTC))
+
+	s, err = FromRobfig("@every 1h30m")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.Add(90*time.Minute))
+
+	s, err = FromRobfig("@hourly")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.Add(time.Hour))
+
+	s, err = FromRobfig("CRON_TZ=Asia/Shanghai 0 9 * * *")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))
+
+	s, err = FromRobfig("CRON_TZ=Not/Exists 0 9 * * *")
+	a.Error(err).Nil(s)
+
+	s, err = FromRobfig("9 * * *")
+	a.Error(err).Nil(s)
+}
+
+// 与 robfig/cron 文档中的示例比较
+func TestFromRobfig_star(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2020, 1, 1, 9, 0, 17, 0, time.UTC)
+
+	next := func(spec string, n int) []time.Time {
+		s, err := FromRobfig(spec)
+		a.NotError(err, "%s 出错 %s", spec, err).NotNil(s)
+		return schedulers.NextN(s, start, n)
+	}
+
+	// 09:30 这一分钟内的每一秒
+	times := next("* 30 9 * * *", 61)
+	a.Equal(times[0], time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC)).
+		Equal(times[59], time.Date(2020, 1, 1, 9, 30, 59, 0, time.UTC)).
+		Equal(times[60], time.Date(2020, 1, 2, 9, 30, 0, 0, time.UTC))
+
+	// 每秒
+	a.Equal(next("* * * * * *", 2), []time.Time{
+		time.Date(2020, 1, 1, 9, 0, 18, 0, time.UTC),
+		time.Date(2020, 1, 1, 9, 0, 19, 0, time.UTC),
+	})
+
+	// 每分钟的第 0 秒
+	a.Equal(next("* * * * *", 2), []time.Time{
+		time.Date(2020, 1, 1, 9, 1, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 9, 2, 0, 0, time.UTC),
+	})
+
+	// 9 点这一小时内的每分钟
+	a.Equal(next("0 * 9 * * *", 2), []time.Time{
+		time.Date(2020, 1, 1, 9, 1, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 9, 2, 0, 0, time.UTC),
+	})
+
+	// 每小时的第 30 分
+	a.Equal(next("30 * * * *", 2), []time.Time{
+		time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC),
+	})
+
+	a.Equal(next("CRON_TZ=UTC */15 * * * * *", 1), []time.Time{time.Date(2020, 1, 1, 9, 0, 30, 0, time.UTC)})
+}
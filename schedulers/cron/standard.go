@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// ParseStandard 解析标准的 5 个字段的 crontab 表达式
+//
+// 5 个字段依次为分、小时、日、月和星期，秒数固定为 0，
+// 方便从 Unix cron 等只精确到分钟的系统中迁移。
+// @ 开头的指令以及 CRON_TZ= 前缀的处理方式与 Parse 相同，opts 也与 Parse 相同。
+// 返回的 FieldError.Index 为补全秒字段之后的索引。
+func ParseStandard(spec string, opts ...Option) (schedulers.Scheduler, error) {
+	loc, rest, found, err := parseTZ(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(rest, "@") {
+		if len(strings.Fields(rest)) != indexSize-1 {
+			return nil, errors.New("长度不正确")
+		}
+		rest = "0 " + rest
+	}
+
+	s, err := Parse(rest, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		return schedulers.InLocation(s, loc), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestParseStandard(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := ParseStandard("30 9 * * 1-5")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Title(), "0 30 9 * * 1-5")
+	a.Equal(s.Next(now), time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC))
+
+	s, err = ParseStandard("*/15 * * * *")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.Add(15*time.Minute))
+
+	s, err = ParseStandard("@daily")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), now.AddDate(0, 0, 1))
+
+	s, err = ParseStandard("TZ=Asia/Shanghai 0 9 * * *")
+	a.NotError(err).NotNil(s)
+	a.Equal(s.Next(now), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))
+
+	s, err = ParseStandard("0 H * * *", Hash("job"))
+	a.NotError(err).NotNil(s)
+
+	// 6 个字段
+	s, err = ParseStandard("0 30 9 * * 1-5")
+	a.Error(err).Nil(s)
+
+	s, err = ParseStandard("9 * * *")
+	a.Error(err).Nil(s)
+
+	s, err = ParseStandard("60 * * * *")
+	a.Error(err).Nil(s)
+
+	s, err = ParseStandard("TZ=Not/Exists 0 9 * * *")
+	a.Error(err).Nil(s)
+}
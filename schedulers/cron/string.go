@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Normalize 返回 spec 规范化之后的形式
+//
+// 相同含义的表达式返回相同的值，可用于对用户提交的表达式去重或是比较差异：
+//  - cron 表达式以及指令返回其 String 的内容，比如 @daily 返回 0 0 0 * * *；
+//  - 展开为多个表达式或是带时区的自定义指令，返回展开之后规范化的内容；
+//  - TZ= 前缀统一为 CRON_TZ=；
+//  - @every 的时长以 time.Duration.String 的格式表示，比如 @every 90m 返回 @every 1h30m0s；
+//  - 以分号分隔的多个表达式，分别规范化之后去重并排序。
+// 由 opts 指定的扩展选项不包含在返回值中，H 以计算后的值表示。
+func Normalize(spec string, opts ...Option) (string, error) {
+	if strings.IndexByte(spec, ';') >= 0 {
+		specs := strings.Split(spec, ";")
+		exists := make(map[string]bool, len(specs))
+		items := make([]string, 0, len(specs))
+		for _, item := range specs {
+			n, err := Normalize(strings.TrimSpace(item), opts...)
+			if err != nil {
+				return "", err
+			}
+
+			for _, n := range strings.Split(n, "; ") { // 自定义指令可能展开为多个表达式
+				if !exists[n] {
+					exists[n] = true
+					items = append(items, n)
+				}
+			}
+		}
+		sort.Strings(items)
+		return strings.Join(items, "; "), nil
+	}
+
+	loc, rest, found, err := parseTZ(spec)
+	if err != nil {
+		return "", err
+	}
+
+	// 展开为多个表达式或是带时区的自定义指令，按展开之后的内容规范化，
+	// 外层的时区仅作用于未指定时区的表达式，与 Parse 相同。
+	directLocker.RLock()
+	d, isDirective := direct[rest]
+	directLocker.RUnlock()
+	if isDirective && (strings.IndexByte(d, ';') >= 0 || hasTZ(d)) {
+		parts := strings.Split(d, ";")
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if found && !hasTZ(part) {
+				part = "CRON_TZ=" + loc.String() + " " + part
+			}
+			parts[i] = part
+		}
+		return Normalize(strings.Join(parts, ";"), opts...)
+	}
+
+	s, err := Parse(rest, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var ret string
+	switch c := s.(type) {
+	case *cron:
+		ret = c.String()
+	case *reboot:
+		ret = c.Title()
+	default:
+		fs := strings.Fields(rest)
+		if fs[0] == "@every" {
+			d, err := time.ParseDuration(fs[1])
+			if err != nil {
+				return "", err
+			}
+			fs[1] = d.String()
+		}
+		ret = strings.Join(fs, " ")
+	}
+
+	if found {
+		ret = "CRON_TZ=" + loc.String() + " " + ret
+	}
+	return ret, nil
+}
+
+// spec 是否带有 CRON_TZ= 或 TZ= 前缀
+func hasTZ(spec string) bool {
+	_, _, found, _ := parseTZ(spec)
+	return found
+}
+
+// String 返回规范化之后的表达式
+//
+// 与 Title 返回原始的表达式不同，String 根据解析后的内容重新生成表达式，
+// 相同含义的表达式返回相同的值，比如 */20 0 0 * * SUN 与 0,20,40 0 0 * * 7 都返回 0,20,40 0 0 * * 0，
+// 方便保存和比较，其返回值可以被 Parse 重新解析。
+// 步长会展开成具体的值，星期中的 7 以 0 表示，H 以计算后的值表示；
+// 由 Option 指定的扩展选项不包含在返回值中。
+func (c *cron) String() string {
+	fs := make([]string, 0, indexSize+1)
+	for i, f := range c.data {
+		var extra []string
+		switch i {
+		case dayIndex:
+			extra = c.dayExtra()
+		case weekIndex:
+			extra = c.weekExtra()
+		}
+		fs = append(fs, formatField(f, bounds[i], extra))
+	}
+
+	if c.years != nil {
+		b := bounds[yearIndex]
+		vals := make([]int, 0, 10)
+		for i, ok := range c.years {
+			if ok {
+				vals = append(vals, i+b.min)
+			}
+		}
+		fs = append(fs, formatValues(vals))
+	}
+
+	return strings.Join(fs, " ")
+}
+
+// 日字段中无法以 fields 表示的部分
+func (c *cron) dayExtra() []string {
+	var extra []string
+	if c.data[dayIndex]&last != 0 {
+		extra = append(extra, "L")
+	}
+
+	b := bounds[dayIndex]
+	for i := b.min; i <= b.max; i++ {
+		if c.nearest.match(i) {
+			extra = append(extra, strconv.Itoa(i)+"W")
+		}
+	}
+
+	if c.nearest&last != 0 {
+		extra = append(extra, "LW")
+	}
+
+	for n := 1; n <= 30; n++ {
+		if c.beforeLast.match(n) {
+			extra = append(extra, "L-"+strconv.Itoa(n))
+		}
+	}
+	return extra
+}
+
+// 星期字段中无法以 fields 表示的部分
+func (c *cron) weekExtra() []string {
+	var extra []string
+	for w, nth := range c.nth {
+		for n := 1; n <= 5; n++ {
+			if nth.match(n) {
+				extra = append(extra, strconv.Itoa(w)+"#"+strconv.Itoa(n))
+			}
+		}
+
+		if nth&last != 0 {
+			extra = append(extra, strconv.Itoa(w)+"L")
+		}
+	}
+	return extra
+}
+
+// 将 fs 格式化成字符串，extra 为附加在最后的内容。
+func formatField(fs fields, b bound, extra []string) string {
+	if fs == any || fs == step {
+		return "*"
+	}
+
+	vals := make([]int, 0, 10)
+	for _, v := range fs.values(b) {
+		vals = append(vals, int(v))
+	}
+
+	if len(vals) == 0 {
+		return strings.Join(extra, ",")
+	}
+
+	if len(extra) == 0 {
+		return formatValues(vals)
+	}
+	return formatValues(vals) + "," + strings.Join(extra, ",")
+}
+
+// 将从小到大排列的 vals 格式化成字符串，连续的值以范围表示。
+func formatValues(vals []int) string {
+	items := make([]string, 0, len(vals))
+	for i := 0; i < len(vals); {
+		j := i
+		for j+1 < len(vals) && vals[j+1] == vals[j]+1 {
+			j++
+		}
+
+		switch {
+		case j == i:
+			items = append(items, strconv.Itoa(vals[i]))
+		case j == i+1: // 两个连续的值，以逗号分隔更直观。
+			items = append(items, strconv.Itoa(vals[i]), strconv.Itoa(vals[j]))
+		default:
+			items = append(items, strconv.Itoa(vals[i])+"-"+strconv.Itoa(vals[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(items, ",")
+}
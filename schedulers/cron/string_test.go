@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+
+package cron
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestCron_String(t *testing.T) {
+	a := assert.New(t)
+
+	data := []struct {
+		spec, canonical string
+	}{
+		{spec: "*/20 0 0 * * SUN", canonical: "0,20,40 0 0 * * 0"},
+		{spec: "0,20,40 0 0 * * 7", canonical: "0,20,40 0 0 * * 0"},
+		{spec: "* 5 * * * *", canonical: "* 5 * * * *"},
+		{spec: "0 0 9 * JAN-MAR,jul MON-FRI", canonical: "0 0 9 * 1-3,7 1-5"},
+		{spec: "0 0 0 1,2,3,5,6 * *", canonical: "0 0 0 1-3,5,6 * *"},
+		{spec: "0 0 0 L,15W,1,LW * *", canonical: "0 0 0 1,L,15W,LW * *"},
+		{spec: "0 0 0 15W * *", canonical: "0 0 0 15W * *"},
+		{spec: "0 0 0 L-10,1,L-2 * *", canonical: "0 0 0 1,L-2,L-10 * *"},
+		{spec: "0 0 0 ? * FRI#3,1", canonical: "0 0 0 * * 1,5#3"},
+		{spec: "0 0 0 ? * FRIL,7L", canonical: "0 0 0 * * 0L,5L"},
+		{spec: "0 0 0 1 1 * 2026-2028,2030", canonical: "0 0 0 1 1 * 2026-2028,2030"},
+		{spec: "@daily", canonical: "0 0 0 * * *"},
+	}
+
+	for _, item := range data {
+		s, err := Parse(item.spec)
+		a.NotError(err, "%s 解析出错 %s", item.spec, err).NotNil(s)
+
+		c := s.(*cron)
+		a.Equal(c.String(), item.canonical)
+		if item.spec[0] != '@' { // 指令的标题为其对应的表达式
+			a.Equal(c.Title(), item.spec)
+		}
+
+		// 重新解析之后的内容相同
+		s2, err := Parse(c.String())
+		a.NotError(err).NotNil(s2)
+		c2 := s2.(*cron)
+		a.Equal(c2.data, c.data).
+			Equal(c2.nearest, c.nearest).
+			Equal(c2.nth, c.nth).
+			Equal(c2.years, c.years).
+			Equal(c2.String(), c.String())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a := assert.New(t)
+
+	data := []struct {
+		spec, normalized string
+	}{
+		{spec: "*/20 0 0 * * SUN", normalized: "0,20,40 0 0 * * 0"},
+		{spec: "0 0 0 6,1-5 * *", normalized: "0 0 0 1-6 * *"},
+		{spec: "@daily", normalized: "0 0 0 * * *"},
+		{spec: "@midnight", normalized: "0 0 0 * * *"},
+		{spec: "@reboot", normalized: "@reboot"},
+		{spec: "@every  90m", normalized: "@every 1h30m0s"},
+		{spec: "@fiscal-year-end   4", normalized: "@fiscal-year-end 4"},
+		{spec: "TZ=Asia/Shanghai  @daily", normalized: "CRON_TZ=Asia/Shanghai 0 0 0 * * *"},
+		{spec: "0 0 12 * * 6; @daily;0 0 0 * * *", normalized: "0 0 0 * * *; 0 0 12 * * 6"},
+	}
+
+	for _, item := range data {
+		n, err := Normalize(item.spec)
+		a.NotError(err, "%s 出错 %s", item.spec, err).
+			Equal(n, item.normalized, "%s 的返回值 %s 不正确", item.spec, n)
+
+		// 规范化之后的内容不再变化
+		n2, err := Normalize(n)
+		a.NotError(err).Equal(n2, n)
+	}
+
+	// 展开为多个表达式或是带时区的自定义指令
+	a.NotError(RegisterDirective("@normalize-union", "0 0 12 * * 6; @daily"))
+	defer unregisterDirective("@normalize-union")
+	a.NotError(RegisterDirective("@normalize-tz", "TZ=Asia/Shanghai 0 0 9 * * *"))
+	defer unregisterDirective("@normalize-tz")
+	a.NotError(RegisterDirective("@normalize-plain", "0 0 9 * * 1-5"))
+	defer unregisterDirective("@normalize-plain")
+	directives := []struct {
+		spec, normalized string
+	}{
+		{spec: "@normalize-union", normalized: "0 0 0 * * *; 0 0 12 * * 6"},
+		{spec: "@normalize-tz", normalized: "CRON_TZ=Asia/Shanghai 0 0 9 * * *"},
+		{spec: "@normalize-plain", normalized: "0 0 9 * * 1-5"},
+		{spec: "CRON_TZ=UTC @normalize-union", normalized: "CRON_TZ=UTC 0 0 0 * * *; CRON_TZ=UTC 0 0 12 * * 6"},
+		{spec: "CRON_TZ=UTC @normalize-tz", normalized: "CRON_TZ=Asia/Shanghai 0 0 9 * * *"},
+		{spec: "@normalize-tz; @normalize-union", normalized: "0 0 0 * * *; 0 0 12 * * 6; CRON_TZ=Asia/Shanghai 0 0 9 * * *"},
+		{spec: "@daily; @normalize-union", normalized: "0 0 0 * * *; 0 0 12 * * 6"},
+	}
+	for _, item := range directives {
+		n, err := Normalize(item.spec)
+		a.NotError(err, "%s 出错 %s", item.spec, err).
+			Equal(n, item.normalized, "%s 的返回值 %s 不正确", item.spec, n)
+
+		n2, err := Normalize(n)
+		a.NotError(err).Equal(n2, n)
+	}
+
+	n, err := Normalize("H 0 0 * * *", Hash("job"))
+	a.NotError(err).NotEqual(n[0], 'H')
+
+	_, err = Normalize("0 0 25 * * *")
+	a.Error(err)
+	_, err = Normalize("TZ=Not/Exists @daily")
+	a.Error(err)
+	_, err = Normalize("@daily; 0 0 25 * * *")
+	a.Error(err)
+}
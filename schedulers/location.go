@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"fmt"
+	"time"
+)
+
+type location struct {
+	s   Scheduler
+	loc *time.Location
+}
+
+// 被包装的调度器实现了 PrevScheduler 时采用的类型
+type prevLocation struct {
+	location
+}
+
+// InLocation 返回在 loc 时区中计算时间的调度器
+//
+// 传递给 s.Next 的参数会被转换到 loc 时区，其返回值再转换回 last 的时区。
+// 比如 cron 表达式 0 0 9 * * * 在 InLocation 之后，表示 loc 时区中的 9 点，
+// 而不是 Server 所在时区的 9 点。
+//
+// 仅在 s 实现了 PrevScheduler 时，返回值才会实现 PrevScheduler 接口。
+func InLocation(s Scheduler, loc *time.Location) Scheduler {
+	l := location{s: s, loc: loc}
+	if _, ok := s.(PrevScheduler); ok {
+		return &prevLocation{location: l}
+	}
+	return &l
+}
+
+func (l *location) Next(last time.Time) time.Time {
+	next := l.s.Next(last.In(l.loc))
+	if next.IsZero() {
+		return next
+	}
+	return next.In(last.Location())
+}
+
+// Prev 实现 PrevScheduler 接口
+func (l *prevLocation) Prev(t time.Time) time.Time {
+	prev := l.s.(PrevScheduler).Prev(t.In(l.loc))
+	if prev.IsZero() {
+		return prev
+	}
+	return prev.In(t.Location())
+}
+
+// Stateful 实现 Stateful 接口，与被包装的调度器相同。
+func (l *location) Stateful() bool {
+	return IsStateful(l.s)
+}
+
+func (l *location) Title() string {
+	return fmt.Sprintf("%s (%s)", l.s.Title(), l.loc)
+}
+
+func (l *location) String() string {
+	return l.Title()
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+var (
+	_ Scheduler     = &location{}
+	_ fmt.Stringer  = &location{}
+	_ PrevScheduler = &prevLocation{}
+	_ fmt.Stringer  = &prevLocation{}
+)
+
+// 每天 9 点执行
+type daily struct{}
+
+func (daily) Next(last time.Time) time.Time {
+	next := time.Date(last.Year(), last.Month(), last.Day(), 9, 0, 0, 0, last.Location())
+	if !next.After(last) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func (daily) Prev(t time.Time) time.Time {
+	prev := time.Date(t.Year(), t.Month(), t.Day(), 9, 0, 0, 0, t.Location())
+	if !prev.Before(t) {
+		prev = prev.AddDate(0, 0, -1)
+	}
+	return prev
+}
+
+func (daily) Title() string { return "daily" }
+
+// 返回零值
+type never struct{}
+
+func (never) Next(time.Time) time.Time { return time.Time{} }
+
+func (never) Title() string { return "never" }
+
+func TestInLocation(t *testing.T) {
+	a := assert.New(t)
+	loc := time.FixedZone("UTC+8", 8*60*60)
+
+	s := InLocation(daily{}, loc)
+	a.Equal(s.Title(), "daily (UTC+8)")
+
+	// UTC 的 00:00 即 UTC+8 的 08:00，下一次为 UTC+8 的 09:00，即 UTC 的 01:00。
+	next := s.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	a.Equal(next, time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))
+	a.Equal(next.Location(), time.UTC)
+
+	next = s.Next(next)
+	a.Equal(next, time.Date(2020, 1, 2, 1, 0, 0, 0, time.UTC))
+
+	next = InLocation(never{}, loc).Next(time.Now())
+	a.True(next.IsZero())
+}
+
+func TestLocation_Prev(t *testing.T) {
+	a := assert.New(t)
+	loc := time.FixedZone("UTC+8", 8*60*60)
+
+	// UTC 的 00:00 即 UTC+8 的 08:00，上一次为前一天 UTC+8 的 09:00，即 UTC 的 01:00。
+	prev := InLocation(daily{}, loc).(PrevScheduler).Prev(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	a.Equal(prev, time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))
+	a.Equal(prev.Location(), time.UTC)
+
+	// 被包装的调度器未实现 PrevScheduler 接口
+	_, ok := InLocation(never{}, loc).(PrevScheduler)
+	a.False(ok)
+}
@@ -21,3 +21,51 @@ type Scheduler interface {
 	// Title 返回用于描述当前算法的一个简短介绍
 	Title() string
 }
+
+// PrevScheduler 可以计算之前执行时间的调度算法
+//
+// 这是一个可选的接口，可用于在重启之后检测错过的执行时间。
+type PrevScheduler interface {
+	Scheduler
+
+	// 获取 t 之前最近一次应该执行的时间，不包含 t 本身
+	//
+	// 返回值的时区应该和 t 相同，零值表示在 t 之前没有需要执行的时间。
+	Prev(t time.Time) time.Time
+}
+
+// Stateful 调用 Next 会改变其内部状态的调度算法
+//
+// 这是一个可选的接口，比如根据执行结果调整时间间隔的调度器，
+// 其 Next 只应该在任务实际执行之后调用，Job.NextN、Server.ICS 等预览功能不会对其调用 Next。
+type Stateful interface {
+	Scheduler
+
+	// Stateful 返回调用 Next 是否会改变内部状态
+	//
+	// 返回值可以随状态变化，比如仅第一次调用 Next 会改变状态的调度器，之后可以返回 false。
+	Stateful() bool
+}
+
+// IsStateful 判断调用 s.Next 是否会改变 s 的内部状态
+func IsStateful(s Scheduler) bool {
+	st, ok := s.(Stateful)
+	return ok && st.Stateful()
+}
+
+// NextN 返回 s 在 start 之后的 n 个执行时间
+//
+// 调度终结时，即 Next 返回零值时，返回的数量会少于 n，且不包含零值。
+// 会改变 s 的内部状态的调度器，参考 Stateful，返回 nil。
+// 返回值的时区与 start 相同。
+func NextN(s Scheduler, start time.Time, n int) []time.Time {
+	if n <= 0 || IsStateful(s) {
+		return nil
+	}
+
+	ret := make([]time.Time, 0, n)
+	for next := s.Next(start); !next.IsZero() && len(ret) < n; next = s.Next(next) {
+		ret = append(ret, next)
+	}
+	return ret
+}
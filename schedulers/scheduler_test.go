@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+// 执行 n 次之后终结
+type times struct{ n int }
+
+func (t *times) Next(last time.Time) time.Time {
+	if t.n <= 0 {
+		return time.Time{}
+	}
+	t.n--
+	return last.Add(time.Hour)
+}
+
+func (t *times) Title() string { return "times" }
+
+// Next 会改变状态的调度器
+type stateful struct{ times }
+
+func (s *stateful) Stateful() bool { return true }
+
+func TestNextN(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a.Equal(NextN(daily{}, start, 3), []time.Time{
+		time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 9, 0, 0, 0, time.UTC),
+	})
+
+	// 调度终结
+	a.Equal(NextN(&times{n: 2}, start, 5), []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour)})
+	a.Empty(NextN(never{}, start, 5))
+
+	a.Nil(NextN(daily{}, start, 0))
+	a.Nil(NextN(daily{}, start, -1))
+
+	// 不会调用 Next 改变其状态
+	s := &stateful{times{n: 2}}
+	a.Nil(NextN(s, start, 5))
+	a.Equal(s.n, 2)
+}
+
+func TestIsStateful(t *testing.T) {
+	a := assert.New(t)
+	s := &stateful{times{n: 2}}
+
+	a.True(IsStateful(s)).
+		False(IsStateful(daily{})).
+		True(IsStateful(InLocation(s, time.UTC))).
+		False(IsStateful(InLocation(daily{}, time.UTC))).
+		True(IsStateful(Union(daily{}, s))).
+		False(IsStateful(Union(daily{}, never{})))
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+
+package ticker
+
+import (
+	"time"
+
+	"github.com/issue9/scheduled/schedulers"
+)
+
+// Interval 可直接用于配置文件中的定时器
+//
+// 实现了 encoding.TextMarshaler 和 encoding.TextUnmarshaler 接口，
+// 文本格式与 time.ParseDuration 相同，比如 1h30m，解析之后等同于 New(d, false) 的返回值。
+// 零值的 Scheduler 为 nil，不能直接使用，其文本形式为 0s，
+// 0s 以及空字符串都会被解析为零值，方便配置中未设置的字段可以正常地保存和加载。
+type Interval struct {
+	schedulers.Scheduler
+	dur time.Duration
+}
+
+// NewInterval 声明 Interval
+func NewInterval(d time.Duration) (Interval, error) {
+	s, err := New(d, false)
+	if err != nil {
+		return Interval{}, err
+	}
+	return Interval{Scheduler: s, dur: d}, nil
+}
+
+// Duration 返回时间段
+func (i Interval) Duration() time.Duration {
+	return i.dur
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+func (i Interval) MarshalText() ([]byte, error) {
+	return []byte(i.dur.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+func (i *Interval) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*i = Interval{}
+		return nil
+	}
+
+	d, err := time.ParseDuration(string(data))
+	if err != nil {
+		return err
+	}
+	if d == 0 {
+		*i = Interval{}
+		return nil
+	}
+
+	v, err := NewInterval(d)
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+func (i Interval) String() string {
+	return i.dur.String()
+}
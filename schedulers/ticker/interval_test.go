@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+
+package ticker
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/scheduled/schedulers"
+)
+
+var (
+	_ schedulers.Scheduler     = Interval{}
+	_ fmt.Stringer             = Interval{}
+	_ encoding.TextMarshaler   = Interval{}
+	_ encoding.TextUnmarshaler = &Interval{}
+)
+
+func TestInterval(t *testing.T) {
+	a := assert.New(t)
+
+	type config struct {
+		Ping Interval `json:"ping"`
+	}
+
+	conf := &config{}
+	a.NotError(json.Unmarshal([]byte(`{"ping":"90m"}`), conf))
+	a.Equal(conf.Ping.Duration(), 90*time.Minute)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.Equal(conf.Ping.Next(now), now.Add(90*time.Minute))
+
+	data, err := json.Marshal(conf)
+	a.NotError(err).Equal(string(data), `{"ping":"1h30m0s"}`)
+
+	a.Error(json.Unmarshal([]byte(`{"ping":"1x"}`), conf))
+	a.Error(json.Unmarshal([]byte(`{"ping":"10ms"}`), conf))
+	a.Error(json.Unmarshal([]byte(`{"ping":"-1h"}`), conf))
+
+	// 零值
+	data, err = json.Marshal(&config{})
+	a.NotError(err).Equal(string(data), `{"ping":"0s"}`)
+	conf = &config{}
+	a.NotError(json.Unmarshal([]byte(`{"ping":"1h"}`), conf))
+	a.NotError(json.Unmarshal(data, conf))
+	a.Nil(conf.Ping.Scheduler).Equal(conf.Ping.Duration(), 0)
+	a.NotError(json.Unmarshal([]byte(`{"ping":""}`), conf))
+	a.Nil(conf.Ping.Scheduler)
+
+	i, err := NewInterval(time.Millisecond)
+	a.Error(err).Nil(i.Scheduler)
+}
@@ -15,6 +15,11 @@ type ticker struct {
 	dur   time.Duration
 	title string
 	imm   bool
+
+	// 是否以 start 为锚点计算时间，
+	// start 为零值表示以第一次调用 Next 时的 last 为锚点。
+	anchored bool
+	start    time.Time
 }
 
 // New 声明一个固定时间段的定时任务
@@ -33,15 +38,73 @@ func New(d time.Duration, imm bool) (schedulers.Scheduler, error) {
 	}, nil
 }
 
+// NewAnchored 声明一个以固定锚点计算时间的定时任务
+//
+// 与 New 不同，返回的时间总是 start 加上 d 的整数倍，
+// 即使每次任务的实际执行时间有所延迟，也不会累积误差。
+// start 为零值时，以第一次调用 Next 时的参数作为锚点。
+func NewAnchored(d time.Duration, start time.Time, imm bool) (schedulers.Scheduler, error) {
+	s, err := New(d, imm)
+	if err != nil {
+		return nil, err
+	}
+
+	t := s.(*ticker)
+	t.anchored = true
+	t.start = start
+	return t, nil
+}
+
 func (t *ticker) Next(last time.Time) time.Time {
 	if t.imm {
 		t.imm = false
 		return time.Now().In(last.Location())
 	}
 
-	return last.Add(t.dur)
+	if !t.anchored {
+		return last.Add(t.dur)
+	}
+
+	if t.start.IsZero() {
+		t.start = last
+	}
+
+	if last.Before(t.start) {
+		return t.start.In(last.Location())
+	}
+
+	k := last.Sub(t.start)/t.dur + 1
+	return t.start.Add(k * t.dur).In(last.Location())
+}
+
+// Stateful 实现 schedulers.Stateful 接口
+//
+// 需要立即执行的第一次，或是尚未确定锚点时，调用 Next 会改变内部状态。
+func (t *ticker) Stateful() bool {
+	return t.imm || (t.anchored && t.start.IsZero())
+}
+
+// Prev 实现 schedulers.PrevScheduler 接口
+//
+// 由 New 声明的定时器返回 last 减去时间段的值；由 NewAnchored 声明的定时器，
+// 返回小于 last 的锚点加上时间段的整数倍，锚点尚未确定或是 last 不大于锚点时返回零值。
+func (t *ticker) Prev(last time.Time) time.Time {
+	if !t.anchored {
+		return last.Add(-t.dur)
+	}
+
+	if t.start.IsZero() || !last.After(t.start) {
+		return time.Time{}
+	}
+
+	k := (last.Sub(t.start) - 1) / t.dur
+	return t.start.Add(k * t.dur).In(last.Location())
 }
 
 func (t *ticker) Title() string {
 	return t.title
 }
+
+func (t *ticker) String() string {
+	return t.title
+}
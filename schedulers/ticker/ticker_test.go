@@ -3,6 +3,7 @@
 package ticker
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,7 +11,12 @@ import (
 	"github.com/issue9/scheduled/schedulers"
 )
 
-var _ schedulers.Scheduler = &ticker{}
+var (
+	_ schedulers.Scheduler     = &ticker{}
+	_ schedulers.PrevScheduler = &ticker{}
+	_ schedulers.Stateful      = &ticker{}
+	_ fmt.Stringer             = &ticker{}
+)
 
 func TestTicker(t *testing.T) {
 	a := assert.New(t)
@@ -46,3 +52,98 @@ func TestTicker(t *testing.T) {
 	next2 = s.Next(next1)
 	a.Equal(next2.Unix(), now.Add(5*time.Minute).Unix())
 }
+
+func TestNewAnchored(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := NewAnchored(300*time.Microsecond, time.Time{}, false)
+	a.Error(err).Nil(s)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, err = NewAnchored(time.Minute, start, false)
+	a.NotError(err).NotNil(s)
+
+	// 早于锚点
+	a.Equal(s.Next(start.Add(-time.Hour)), start)
+
+	next := s.Next(start)
+	a.Equal(next, start.Add(time.Minute))
+
+	// 延迟执行，不会累积误差
+	next = s.Next(next.Add(10 * time.Second))
+	a.Equal(next, start.Add(2*time.Minute))
+
+	// 延迟超过一个周期
+	next = s.Next(next.Add(90 * time.Second))
+	a.Equal(next, start.Add(4*time.Minute))
+
+	// 继承时区
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	next = s.Next(start.In(loc))
+	a.Equal(next.Location(), loc).Equal(next.Unix(), start.Add(time.Minute).Unix())
+
+	// start 为零值
+	s, err = NewAnchored(time.Minute, time.Time{}, false)
+	a.NotError(err).NotNil(s)
+	now := time.Now()
+	next = s.Next(now)
+	a.True(next.Equal(now.Add(time.Minute)))
+	next = s.Next(next.Add(time.Second))
+	a.True(next.Equal(now.Add(2 * time.Minute)))
+}
+
+func TestTicker_Prev(t *testing.T) {
+	a := assert.New(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := New(time.Minute, false)
+	a.NotError(err).NotNil(s)
+	p := s.(schedulers.PrevScheduler)
+	a.Equal(p.Prev(start), start.Add(-time.Minute))
+
+	s, err = NewAnchored(time.Minute, start, false)
+	a.NotError(err).NotNil(s)
+	p = s.(schedulers.PrevScheduler)
+	a.True(p.Prev(start).IsZero())
+	a.True(p.Prev(start.Add(-time.Hour)).IsZero())
+	a.Equal(p.Prev(start.Add(time.Second)), start)
+	a.Equal(p.Prev(start.Add(time.Minute)), start)
+	a.Equal(p.Prev(start.Add(90*time.Second)), start.Add(time.Minute))
+
+	// 继承时区
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	prev := p.Prev(start.Add(2 * time.Minute).In(loc))
+	a.Equal(prev.Location(), loc).Equal(prev.Unix(), start.Add(time.Minute).Unix())
+
+	// 锚点未确定
+	s, err = NewAnchored(time.Minute, time.Time{}, false)
+	a.NotError(err).NotNil(s)
+	a.True(s.(schedulers.PrevScheduler).Prev(start).IsZero())
+}
+
+func TestTicker_Stateful(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := New(time.Minute, false)
+	a.NotError(err).NotNil(s)
+	a.False(schedulers.IsStateful(s)).
+		Equal(schedulers.NextN(s, now, 2), []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)})
+
+	// 预览不会消耗掉立即执行的那一次
+	s, err = New(time.Minute, true)
+	a.NotError(err).NotNil(s)
+	a.True(schedulers.IsStateful(s)).Nil(schedulers.NextN(s, now, 2))
+	before := time.Now()
+	next := s.Next(now)
+	a.False(next.Before(before)).False(next.After(time.Now()))
+	a.False(schedulers.IsStateful(s))
+
+	// 预览不会确定锚点
+	s, err = NewAnchored(time.Minute, time.Time{}, false)
+	a.NotError(err).NotNil(s)
+	a.True(schedulers.IsStateful(s)).Nil(schedulers.NextN(s, now.Add(30*time.Second), 2))
+	a.Equal(s.Next(now), now.Add(time.Minute))
+	a.False(schedulers.IsStateful(s)).
+		Equal(schedulers.NextN(s, now.Add(30*time.Second), 2), []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)})
+}
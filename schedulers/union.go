@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"strings"
+	"time"
+)
+
+type union []Scheduler
+
+// 所有调度器都实现了 PrevScheduler 时采用的类型
+type prevUnion struct {
+	union
+}
+
+// Union 返回由多个调度器组合而成的调度器
+//
+// Next 返回所有调度器中最早的执行时间，已经终结的调度器会被忽略，
+// 所有调度器都终结时才返回零值。
+// 对于无法用单个 cron 表达式描述的时间，比如工作日的 3 点加上周六的 12 点，
+// 可以将两个表达式组合在一起。
+//
+// 仅在所有调度器都实现了 PrevScheduler 时，返回值才会实现 PrevScheduler 接口。
+func Union(s ...Scheduler) Scheduler {
+	if len(s) == 1 {
+		return s[0]
+	}
+
+	for _, item := range s {
+		if _, ok := item.(PrevScheduler); !ok {
+			return union(s)
+		}
+	}
+	return prevUnion{union: s}
+}
+
+func (u union) Next(last time.Time) time.Time {
+	var next time.Time
+	for _, s := range u {
+		if t := s.Next(last); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// Prev 实现 PrevScheduler 接口，返回所有调度器中最晚的时间。
+func (u prevUnion) Prev(t time.Time) time.Time {
+	var prev time.Time
+	for _, s := range u.union {
+		if pt := s.(PrevScheduler).Prev(t); pt.After(prev) {
+			prev = pt
+		}
+	}
+	return prev
+}
+
+// Stateful 实现 Stateful 接口，任意一个调度器会改变内部状态即返回 true。
+func (u union) Stateful() bool {
+	for _, s := range u {
+		if IsStateful(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u union) Title() string {
+	titles := make([]string, 0, len(u))
+	for _, s := range u {
+		titles = append(titles, s.Title())
+	}
+	return strings.Join(titles, "; ")
+}
+
+func (u union) String() string {
+	return u.Title()
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+
+package schedulers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+var (
+	_ Scheduler     = union{}
+	_ fmt.Stringer  = union{}
+	_ PrevScheduler = prevUnion{}
+	_ fmt.Stringer  = prevUnion{}
+)
+
+func TestUnion(t *testing.T) {
+	a := assert.New(t)
+	loc := time.FixedZone("UTC+8", 8*60*60)
+
+	a.Equal(Union(daily{}), daily{})
+
+	// UTC 的 9 点为 UTC+8 的 17 点
+	s := Union(daily{}, InLocation(daily{}, loc), never{})
+	a.Equal(s.Title(), "daily; daily (UTC+8); never")
+
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	a.Equal(NextN(s, start, 3), []time.Time{
+		time.Date(2020, 1, 2, 1, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 1, 0, 0, 0, time.UTC),
+	})
+
+	a.True(Union(never{}, never{}).Next(start).IsZero())
+
+	// Prev
+	_, ok := s.(PrevScheduler) // never 未实现 PrevScheduler
+	a.False(ok)
+
+	p := Union(daily{}, InLocation(daily{}, loc)).(PrevScheduler)
+	a.Equal(p.Prev(start), time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC))
+	a.Equal(p.Prev(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)), time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))
+}
@@ -8,19 +8,60 @@ import (
 	"time"
 )
 
+// 日志的输出级别
+const (
+	// LogError 仅输出错误信息
+	LogError LogLevel = iota
+
+	// LogInfo 输出任务的启动以及被跳过的原因等信息，默认值。
+	LogInfo
+
+	// LogDebug 在 LogInfo 的基础上，输出任务的完成状态以及每次调度的结果。
+	LogDebug
+)
+
+// LogLevel 日志的输出级别
+//
+// 错误信息输出到 errlog，其它级别的信息都输出到 infolog。
+type LogLevel int8
+
 // Server 管理所有的定时任务
 type Server struct {
-	jobs           []*Job
-	nextScheduled  chan struct{} // 需要指行下一次调度任务
-	scheduleLocker sync.Mutex
-	timer          *time.Timer
-	stop           chan struct{}
+	// 保护 jobs、running、stop 和 panicPolicy，
+	// 需要同时持有 Job.locker 时，必须先获取此锁。
+	locker sync.Mutex
+
+	jobs          []*Job
+	nextScheduled chan struct{} // 需要指行下一次调度任务
+	stop          chan struct{} // 每次调用 Serve 时重新创建，关闭表示停止服务。
+	done          chan struct{} // 每次调用 Serve 时重新创建，在 Serve 退出时关闭。
+
+	// 整个生命周期中只有一个定时器，通过 Reset 重复使用。
+	// armed 表示定时器是否处于计时状态，两者都仅在 Serve 所在的 goroutine 中访问，
+	// 再次调用 Serve 时会通过 done 等待上一次的 Serve 退出，所以不会被同时访问。
+	timer *time.Timer
+	armed bool
 
 	loc             *time.Location
 	running         bool
 	errlog, infolog *log.Logger
+	panicPolicy     PanicPolicy
+	stagger         time.Duration
+	logLevel        LogLevel
+	admission       func(*Job, time.Time) (bool, string)
+	transient       func(error) bool
+	transform       NextTransformer
+	onPanic         func(*PanicError)
 }
 
+// NextTransformer 修改由调度器计算出的下一次执行时间
+//
+// j 为相关的任务，next 为调度器计算出的时间，返回值为实际采用的时间，
+// 返回零值表示不再执行该任务。
+//
+// 调用时持有 j 内部的锁，除了 Name 和 Delay，不能调用 j 的其它方法。
+type NextTransformer func(j *Job, next time.Time) time.Time
+
 // NewServer 声明 Server 对象实例
 //
 // loc 指定当前所采用的时区，若为 nil，则会采用 time.Local 的值；
@@ -34,11 +75,12 @@ func NewServer(loc *time.Location, errlog, infolog *log.Logger) *Server {
 	return &Server{
 		jobs:          make([]*Job, 0, 100),
 		nextScheduled: make(chan struct{}, 1),
-		stop:          make(chan struct{}, 1),
 
-		loc:     loc,
-		errlog:  errlog,
-		infolog: infolog,
+		loc:         loc,
+		errlog:      errlog,
+		infolog:     infolog,
+		panicPolicy: PanicRecover,
+		logLevel:    LogInfo,
 	}
 }
 
@@ -47,30 +89,178 @@ func (s *Server) Location() *time.Location {
 	return s.loc
 }
 
+// PanicPolicy 任务 panic 之后的默认处理方式
+func (s *Server) PanicPolicy() PanicPolicy {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.panicPolicy
+}
+
+// SetPanicPolicy 设置任务 panic 之后的默认处理方式
+//
+// 仅对未通过 Job.SetPanicPolicy 指定处理方式的任务有效，
+// PanicDefault 与 PanicRecover 的效果相同。
+func (s *Server) SetPanicPolicy(p PanicPolicy) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.panicPolicy = p
+}
+
+// LogLevel 当前日志的输出级别
+func (s *Server) LogLevel() LogLevel {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.logLevel
+}
+
+// SetLogLevel 设置日志的输出级别
+//
+// 可以在运行过程中调用，立即生效。
+func (s *Server) SetLogLevel(l LogLevel) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.logLevel = l
+}
+
+// 返回级别为 l 的日志输出通道，当前的输出级别低于 l 时返回 nil。
+func (s *Server) logger(l LogLevel) *log.Logger {
+	s.locker.Lock()
+	level := s.logLevel
+	s.locker.Unlock()
+
+	switch {
+	case l == LogError:
+		return s.errlog
+	case l <= level:
+		return s.infolog
+	default:
+		return nil
+	}
+}
+
+// SetTransient 指定判断错误是否为临时性错误的函数
+//
+// 任务返回的错误被 f 判定为临时性错误时，会以与 ErrNotReady 相同的指数退避方式提前重新执行，
+// 直到退避时间超过正常的下一次执行时间为止；其它错误则直接当作执行失败。
+// 与 ErrNotReady 不同，临时性错误依然会记录在 Job.Err 中，任务状态也为 Failed。
+// 仅对未通过 Job.SetTransient 指定判断函数的任务有效，f 为 nil 表示不作判断，也是默认值。
+func (s *Server) SetTransient(f func(error) bool) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.transient = f
+}
+
+// Stagger 首次执行时间相同的任务被分散的时间段
+func (s *Server) Stagger() time.Duration {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.stagger
+}
+
+// SetStagger 将首次执行时间相同的任务分散到 spread 时间段内执行
+//
+// 在调用 Serve 时，首次执行时间完全相同的任务会按注册顺序依次错开 spread/n，
+// n 为这些任务的数量，防止大量 @hourly 之类的任务在同一时刻执行。
+// 仅影响首次执行时间，之后的执行时间依然由调度器决定。
+// spread 为 0 表示不作处理，也是默认值，对之后调用的 Serve 才有效。
+func (s *Server) SetStagger(spread time.Duration) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.stagger = spread
+}
+
+// SetAdmission 设置任务执行前的准入检测
+//
+// 每次执行任务之前都会调用 f，参数为任务及其计划的执行时间，
+// 返回 false 表示跳过本次执行，reason 为跳过的原因，会以 LogInfo 级别输出到 infolog。
+// 被跳过的任务不会改变状态，直接计算下一次的执行时间。
+// 可用于实现功能开关、负载控制等自定义的限制条件，f 为 nil 表示不作检测。
+//
+// f 在调度的 goroutine 中执行，不应该有耗时的操作。
+func (s *Server) SetAdmission(f func(j *Job, scheduledAt time.Time) (allow bool, reason string)) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.admission = f
+}
+
+// SetNextTransformer 设置修改任务下一次执行时间的函数
+//
+// 每次由调度器计算出下一次执行时间之后都会调用 f，
+// 可用于实现全局的策略，比如对齐到 5 分钟的整点、避开维护时段、添加统一的随机延迟等，
+// 而不需要包装每一个调度器。OnlyBetween 指定的时间段在 f 之后处理。
+// 返回值不晚于上一次执行时间时会被忽略，依然采用 next，防止任务被重复执行。
+//
+// 对所有任务之后计算的执行时间有效，f 为 nil 表示不作修改，也是默认值。
+func (s *Server) SetNextTransformer(f NextTransformer) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	s.transform = f
+	for _, j := range s.jobs {
+		j.locker.Lock()
+		j.transform = f
+		j.locker.Unlock()
+	}
+}
+
 // Serve 运行服务
+//
+// 在 Stop 之后可以再次调用 Serve，此时会重新计算所有任务的下一次执行时间。
 func (s *Server) Serve() error {
+	s.locker.Lock()
 	if s.running {
+		s.locker.Unlock()
 		return ErrRunning
 	}
 
+	// 等待上一次的 Serve 退出，防止两者同时访问定时器。
+	if done := s.done; done != nil {
+		s.locker.Unlock()
+		<-done
+		s.locker.Lock()
+
+		if s.running { // 等待期间已经被其它的 Serve 启动
+			s.locker.Unlock()
+			return ErrRunning
+		}
+	}
+
 	if len(s.jobs) == 0 {
+		s.locker.Unlock()
 		return ErrNoJobs
 	}
 
 	s.running = true
+	stop := make(chan struct{})
+	s.stop = stop
+	done := make(chan struct{})
+	s.done = done
+	defer close(done)
 
 	now := s.now()
 	for _, job := range s.jobs {
 		job.init(now)
 	}
+	if s.stagger > 0 {
+		stagger(s.jobs, s.stagger)
+	}
+	s.locker.Unlock()
 
-	s.nextScheduled <- struct{}{}
+	if s.timer == nil {
+		s.timer = time.NewTimer(time.Hour)
+		s.armed = true
+	}
+	defer s.stopTimer()
+
+	s.schedule()
 	for {
 		select {
-		case <-s.stop:
+		case <-stop:
 			return nil
 		case <-s.nextScheduled:
 			s.schedule()
+		case n := <-s.timerC():
+			s.dispatch(n)
 		}
 	}
 }
@@ -78,23 +268,29 @@ func (s *Server) Serve() error {
 // 调度计划任务
 //
 // 每完成一个计划任务时，都会调用此函数重新计算调度时间，
-// 并重新生成一个最近时间的定时器。如果上一个定时器还未结束，
-// 则会自动结束上一个定时器，schedule 会保证同一时间，
-// 只有一个函数实例在运行。
+// 并将定时器重置为最近的执行时间。如果定时器还未结束，
+// 则会先停止该定时器。
 func (s *Server) schedule() {
-	if s.timer != nil {
-		s.timer.Stop() // 多次调用或是已过期，都不会 panic
-	}
+	s.stopTimer()
 
-	s.scheduleLocker.Lock()
-	defer s.scheduleLocker.Unlock()
+	jobs := s.snapshot()
+	sortJobs(jobs)       // 按执行时间进行排序
+	next := jobs[0].next // 最近需要执行的任务
 
-	sortJobs(s.jobs)       // 按执行时间进行排序
-	next := s.jobs[0].next // 最近需要执行的任务
+	// 排序之后，正在运行和不再需要运行的任务都在最后，
+	// 所以 jobs[0] 为其中之一，表示所有的任务都是这两种状态之一。
+	if next.IsZero() || jobs[0].state == Running {
+		for _, j := range jobs {
+			if j.state == Running { // 等待任务结束之后再次调度
+				return
+			}
 
-	if next.IsZero() { // 没有需要运行的任务
-		s.running = false
-		s.Stop()
+			if j.job.Placeholder() { // 等待 Bind 之后再次调度
+				return
+			}
+		}
+
+		s.Stop() // 没有需要运行的任务
 		return
 	}
 
@@ -103,45 +299,130 @@ func (s *Server) schedule() {
 		dur = 0
 	}
 
-	s.timer = time.NewTimer(dur)
+	s.timer.Reset(dur)
+	s.armed = true
 
-	n, ok := <-s.timer.C // s.timer.C 可能造成 schedule 函数的长时间等待
-	if !ok {
-		return
+	if l := s.logger(LogDebug); l != nil {
+		l.Printf("scheduled: next job %s at %s\n", jobs[0].job.Name(), next.String())
 	}
+}
 
-	for _, j := range s.jobs {
-		if j.State() == Running { // 上一次任务还没结束，则跳过该任务
+// 执行所有在 n 之前需要执行的任务，并重新调度。
+func (s *Server) dispatch(n time.Time) {
+	s.armed = false // 已经从 timer.C 中读取了值
+
+	s.locker.Lock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	jobs = append(jobs, s.jobs...)
+	policy := s.panicPolicy
+	admission := s.admission
+	transient := s.transient
+	onPanic := s.onPanic
+	s.locker.Unlock()
+
+	for _, j := range jobs {
+		if admission != nil {
+			if at, ok := j.due(n); ok {
+				if allow, reason := admission(j, at); !allow {
+					j.skip(n)
+					if l := s.logger(LogInfo); l != nil {
+						l.Printf("scheduled: skip job %s at %s: %s\n", j.Name(), at.String(), reason)
+					}
+					continue
+				}
+			}
+		}
+
+		// 在启动 goroutine 之前设置状态，
+		// 防止紧接着的 schedule() 将该任务再次当作需要执行的任务。
+		if !j.start(n) {
 			continue
 		}
 
-		// 因为是按执行顺序排序，如果当前任务不需要执行了，那之后的肯定也不需要
-		if j.next.IsZero() || j.next.After(n) {
-			break
+		go func(j *Job) {
+			j.run(policy, transient, onPanic, s.logger(LogError), s.logger(LogInfo))
+			if l := s.logger(LogDebug); l != nil {
+				l.Printf("scheduled: job %s finished, state %s\n", j.Name(), j.State())
+			}
+			s.reschedule()
+		}(j)
+	}
+
+	s.schedule()
+}
+
+// 将下一次执行时间相同的任务均匀地分散到 spread 时间段内
+func stagger(jobs []*Job, spread time.Duration) {
+	groups := make(map[int64][]*Job, len(jobs))
+	for _, j := range jobs {
+		if next := j.Next(); !next.IsZero() {
+			groups[next.UnixNano()] = append(groups[next.UnixNano()], j)
+		}
+	}
+
+	for _, group := range groups {
+		for i, j := range group {
+			j.locker.Lock()
+			j.next = j.next.Add(spread * time.Duration(i) / time.Duration(len(group)))
+			j.locker.Unlock()
+		}
+	}
+}
+
+// 当前所有任务状态的快照
+//
+// 在快照上排序和比较，不会受到其它 goroutine 修改任务状态的影响。
+func (s *Server) snapshot() []snapshot {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	jobs := make([]snapshot, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j.snapshot())
+	}
+	return jobs
+}
+
+// 停止定时器
+//
+// 如果定时器已经触发但还未读取其值，则清空该值，保证之后的 Reset 不会读到过期的值。
+func (s *Server) stopTimer() {
+	if !s.armed {
+		return
+	}
+
+	if !s.timer.Stop() {
+		select {
+		case <-s.timer.C:
+		default:
 		}
+	}
+	s.armed = false
+}
 
-		j.at = n
-		go j.run(s.errlog, s.infolog)
+// 当前定时器的通道，定时器未计时时返回 nil，读取 nil 通道会一直阻塞。
+func (s *Server) timerC() <-chan time.Time {
+	if !s.armed {
+		return nil
 	}
-	s.nextScheduled <- struct{}{}
+	return s.timer.C
 }
 
 // Stop 停止当前服务
 func (s *Server) Stop() {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
 	if !s.running {
 		return
 	}
 
 	s.running = false
 
-	if s.timer != nil {
-		s.timer.Stop()
-	}
-
 	// NOTE: 不能通过关闭 nextScheduled 来结束 Server。
-	// 因为 schedule() 是异步执行的，
-	// 会源源不断地推内容到 nextJob，如果关闭，可能会造成 schedule() panic
-	s.stop <- struct{}{}
+	// 因为 New 等方法可能在任何时候向 nextScheduled 推送内容，
+	// 如果关闭，可能会造成 panic。stop 在每次调用 Serve 时都会重新创建。
+	close(s.stop)
 }
 
 func (s *Server) now() time.Time {
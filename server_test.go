@@ -4,11 +4,17 @@ package scheduled
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/issue9/assert"
+
+	"github.com/issue9/scheduled/schedulers/ticker"
 )
 
 type incr struct {
@@ -24,6 +30,42 @@ func (i *incr) Title() string {
 	return "递增"
 }
 
+func TestServer_SetPanicPolicy(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+	a.Equal(srv.PanicPolicy(), PanicRecover)
+
+	srv.SetPanicPolicy(PanicPause)
+	a.Equal(srv.PanicPolicy(), PanicPause)
+}
+
+func TestServer_SetStagger(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(time.UTC, nil, nil)
+	a.Equal(srv.Stagger(), 0)
+
+	srv.SetStagger(time.Minute)
+	a.Equal(srv.Stagger(), time.Minute)
+
+	for i := 0; i < 4; i++ {
+		a.NotError(srv.Cron(fmt.Sprintf("hourly-%d", i), succFunc, "@hourly", false))
+	}
+	a.NotError(srv.Cron("daily", succFunc, "@daily", false))
+
+	now := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	for _, j := range srv.jobs {
+		j.init(now)
+	}
+	stagger(srv.jobs, srv.Stagger())
+
+	hour := time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC)
+	a.Equal(srv.jobs[0].Next(), hour).
+		Equal(srv.jobs[1].Next(), hour.Add(15*time.Second)).
+		Equal(srv.jobs[2].Next(), hour.Add(30*time.Second)).
+		Equal(srv.jobs[3].Next(), hour.Add(45*time.Second)).
+		Equal(srv.jobs[4].Next(), time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)) // 唯一的任务不受影响
+}
+
 func TestServer_Serve1(t *testing.T) {
 	a := assert.New(t)
 	srv := NewServer(nil, nil, nil)
@@ -48,7 +90,8 @@ func TestServer_Serve1(t *testing.T) {
 
 	time.Sleep(3 * time.Second)
 	srv.Stop()
-	a.True(ticker1 > ticker2, ticker1, ticker2)
+	t1, t2 := atomic.LoadInt64(&ticker1), atomic.LoadInt64(&ticker2)
+	a.True(t1 > t2, t1, t2)
 }
 
 func TestServer_Serve(t *testing.T) {
@@ -67,6 +110,181 @@ func TestServer_Serve(t *testing.T) {
 	srv.Stop()
 }
 
+func TestServer_Serve_placeholder(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+
+	s, err := ticker.New(time.Second, true)
+	a.NotError(err).NotNil(s)
+	a.NotError(srv.Placeholder("p", s, false))
+
+	// 只有占位任务时，schedule 不应该结束服务
+	srv.running = true
+	srv.stop = make(chan struct{})
+	srv.timer = time.NewTimer(time.Hour)
+	srv.armed = true
+	srv.jobs[0].init(srv.now())
+	srv.schedule()
+	srv.locker.Lock()
+	a.True(srv.running)
+	srv.locker.Unlock()
+	srv.Stop()
+
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+
+	for running := false; !running; {
+		srv.locker.Lock()
+		running = srv.running
+		srv.locker.Unlock()
+	}
+
+	fired := make(chan struct{}, 1)
+	a.NotError(srv.Bind("p", func(time.Time) error {
+		select {
+		case fired <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+
+	select {
+	case <-fired:
+	case <-exit:
+		a.True(false, "只有占位任务时 Serve 不应该退出")
+	case <-time.After(5 * time.Second):
+		a.True(false, "绑定之后任务未执行")
+	}
+
+	srv.Stop()
+	<-exit
+}
+
+func TestServer_Serve_restart(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	var count int64
+	a.NotError(srv.Tick("tick", func(t time.Time) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	}, time.Second, false, false))
+
+	for i := 1; i <= 3; i++ {
+		exit := make(chan struct{}, 1)
+		go func() {
+			a.NotError(srv.Serve())
+			exit <- struct{}{}
+		}()
+
+		time.Sleep(1500 * time.Millisecond)
+		srv.Stop()
+
+		select {
+		case <-exit:
+			a.False(srv.armed, "第 %d 次退出之后定时器依然在计时", i)
+		case <-time.After(time.Second):
+			a.True(false, "第 %d 次调用 Stop 之后 Serve 未退出", i)
+		}
+
+		a.True(atomic.LoadInt64(&count) >= int64(i), "第 %d 次运行，count=%d", i, count)
+	}
+}
+
+// Stop 之后不等待 Serve 退出，立即再次调用 Serve，需要配合 -race 检测数据竞争。
+func TestServer_Serve_restartImmediately(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+
+	fired := make(chan struct{}, 1)
+	a.NotError(srv.Tick("tick", func(time.Time) error {
+		select {
+		case fired <- struct{}{}:
+		default:
+		}
+		return nil
+	}, time.Second, false, false))
+
+	exit := make(chan error, 10)
+	serve := func() {
+		exit <- srv.Serve()
+	}
+
+	go serve()
+	for i := 0; i < 5; i++ {
+		for running := false; !running; {
+			srv.locker.Lock()
+			running = srv.running
+			srv.locker.Unlock()
+		}
+		srv.Stop()
+		go serve()
+	}
+
+	select {
+	case <-fired:
+	case <-time.After(2500 * time.Millisecond):
+		a.True(false, "重启之后任务未执行")
+	}
+
+	srv.Stop()
+	for i := 0; i < 6; i++ {
+		a.NotError(<-exit)
+	}
+}
+
+// 在调度的同时添加、绑定和读取任务，需要配合 -race 检测数据竞争。
+func TestServer_concurrent(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, nil, nil)
+	a.NotNil(srv)
+
+	var count int64
+	f := func(t time.Time) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	}
+	a.NotError(srv.Tick("tick", f, time.Second, true, false))
+
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			name := fmt.Sprintf("job-%d", i)
+			a.NotError(srv.Tick(name, f, time.Second, true, false))
+			a.NotError(srv.Placeholder(name+"-p", &incr{}, false))
+			a.NotError(srv.Bind(name+"-p", f))
+
+			for _, j := range srv.Jobs() {
+				j.Next()
+				j.Prev()
+				j.State()
+				j.Err()
+			}
+			srv.ICS(time.Minute, English)
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+
+	<-done
+	time.Sleep(time.Second)
+	srv.Stop()
+	<-exit
+
+	a.Equal(len(srv.Jobs()), 41)
+	a.True(atomic.LoadInt64(&count) >= 41, count)
+}
+
 func TestServer_Serve_loc(t *testing.T) {
 	a := assert.New(t)
 
@@ -88,3 +306,183 @@ func TestServer_Serve_loc(t *testing.T) {
 	time.Sleep(3 * time.Second)
 	a.Equal(0, buf.Len(), buf.String())
 }
+
+func BenchmarkServer_schedule(b *testing.B) {
+	a := assert.New(b)
+	srv := NewServer(nil, nil, nil)
+	for i := 0; i < 100; i++ {
+		a.NotError(srv.Tick(fmt.Sprintf("tick-%d", i), succFunc, time.Second, false, false))
+	}
+
+	now := srv.now()
+	for _, j := range srv.jobs {
+		j.init(now)
+	}
+	srv.timer = time.NewTimer(time.Hour)
+	srv.armed = true
+	defer srv.stopTimer()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		srv.schedule()
+	}
+}
+
+func TestServer_SetAdmission(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, errlog, nil)
+
+	var allowed, denied int64
+	a.NotError(srv.Tick("allow", func(time.Time) error {
+		atomic.AddInt64(&allowed, 1)
+		return nil
+	}, time.Second, true, false))
+	a.NotError(srv.Tick("deny", func(time.Time) error {
+		atomic.AddInt64(&denied, 1)
+		return nil
+	}, time.Second, true, false))
+
+	var rejected int64
+	srv.SetAdmission(func(j *Job, at time.Time) (bool, string) {
+		a.False(at.IsZero())
+		if j.Name() == "deny" {
+			atomic.AddInt64(&rejected, 1)
+			return false, "denied"
+		}
+		return true, ""
+	})
+
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+	time.Sleep(1500 * time.Millisecond)
+	srv.Stop()
+	<-exit
+
+	a.True(atomic.LoadInt64(&allowed) > 0).
+		Equal(atomic.LoadInt64(&denied), 0).
+		True(atomic.LoadInt64(&rejected) > 0)
+
+	deny := srv.jobs[1]
+	a.Equal(deny.State(), Stopped).True(deny.Prev().IsZero())
+	a.False(deny.Next().IsZero())
+}
+
+func TestServer_SetTransient(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(nil, errlog, nil)
+	errTransient := errors.New("transient")
+
+	var count int64
+	a.NotError(srv.Tick("transient", func(time.Time) error {
+		if atomic.AddInt64(&count, 1) == 1 {
+			return errTransient
+		}
+		return nil
+	}, time.Hour, true, false))
+	srv.SetTransient(func(err error) bool { return errors.Is(err, errTransient) })
+
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+	time.Sleep(1500 * time.Millisecond)
+	srv.Stop()
+	<-exit
+
+	// 首次执行返回临时性错误，在 1 秒内重试。
+	a.Equal(atomic.LoadInt64(&count), 2)
+	j := srv.jobs[0]
+	a.Nil(j.Err()).Equal(j.State(), Stopped)
+}
+
+func TestServer_Serve_reboot(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(time.UTC, errlog, nil)
+
+	var count int64
+	a.NotError(srv.Cron("reboot", func(time.Time) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	}, "@reboot", false))
+
+	// 执行一次之后没有需要执行的任务，Serve 会自动退出。
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(srv.Serve())
+		exit <- struct{}{}
+	}()
+
+	select {
+	case <-exit:
+	case <-time.After(3 * time.Second):
+		srv.Stop()
+		t.Fatal("Serve 未退出")
+	}
+	a.Equal(atomic.LoadInt64(&count), 1)
+}
+
+func TestServer_SetLogLevel(t *testing.T) {
+	a := assert.New(t)
+	erro := log.New(ioutil.Discard, "ERRO", 0)
+	info := log.New(ioutil.Discard, "INFO", 0)
+	srv := NewServer(nil, erro, info)
+	a.Equal(srv.LogLevel(), LogInfo)
+	a.Equal(srv.logger(LogError), erro).
+		Equal(srv.logger(LogInfo), info).
+		Nil(srv.logger(LogDebug))
+
+	srv.SetLogLevel(LogDebug)
+	a.Equal(srv.LogLevel(), LogDebug)
+	a.Equal(srv.logger(LogError), erro).
+		Equal(srv.logger(LogInfo), info).
+		Equal(srv.logger(LogDebug), info)
+
+	srv.SetLogLevel(LogError)
+	a.Equal(srv.logger(LogError), erro).
+		Nil(srv.logger(LogInfo)).
+		Nil(srv.logger(LogDebug))
+}
+
+func TestServer_SetNextTransformer(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(time.UTC, nil, nil)
+	a.NotError(srv.Cron("minutely", succFunc, "0 * * * * *", false))
+
+	// 对齐到 5 分钟
+	srv.SetNextTransformer(func(j *Job, next time.Time) time.Time {
+		a.Equal(j.Name(), "minutely")
+		if r := next.Truncate(5 * time.Minute); !r.Equal(next) {
+			return r.Add(5 * time.Minute)
+		}
+		return next
+	})
+	a.NotError(srv.Cron("ignored", succFunc, "0 * * * * *", false)) // 之后添加的任务同样有效
+
+	j := srv.jobs[0]
+	j.init(time.Date(2020, 1, 1, 10, 1, 0, 0, time.UTC))
+	a.Equal(j.NextN(3), []time.Time{
+		time.Date(2020, 1, 1, 10, 5, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 10, 10, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 10, 15, 0, 0, time.UTC),
+	})
+
+	// 返回值不晚于 last 时被忽略，零值表示不再执行。
+	srv.SetNextTransformer(func(j *Job, next time.Time) time.Time {
+		if next.Hour() >= 11 {
+			return time.Time{}
+		}
+		return next.Add(-time.Hour)
+	})
+	j.init(time.Date(2020, 1, 1, 10, 58, 0, 0, time.UTC))
+	a.Equal(j.NextN(5), []time.Time{time.Date(2020, 1, 1, 10, 59, 0, 0, time.UTC)})
+
+	srv.SetNextTransformer(nil)
+	j.init(time.Date(2020, 1, 1, 10, 1, 0, 0, time.UTC))
+	a.Equal(j.Next(), time.Date(2020, 1, 1, 10, 2, 0, 0, time.UTC))
+	a.Nil(srv.jobs[1].transform)
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"fmt"
+	"time"
+)
+
+// Step 多步骤任务中的一个步骤
+type Step struct {
+	Name string
+	F    JobFunc
+}
+
+// Steps 将多个步骤组合成一个 JobFunc
+//
+// 各个步骤依次执行，任一步骤返回错误或是 panic 时，中止本次执行，
+// 返回的错误会包含该步骤的名称。下一次执行时从失败的步骤开始，
+// 而不是重新执行已经完成的步骤；所有步骤都完成之后，下一次执行会从头开始。
+//
+// 返回的 JobFunc 保存着执行进度，不能同时用于多个任务。
+func Steps(steps ...Step) JobFunc {
+	var next int // 下一次需要执行的步骤
+
+	return func(t time.Time) error {
+		for ; next < len(steps); next++ {
+			step := steps[next]
+			if err := step.F(t); err != nil {
+				return fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+
+		next = 0
+		return nil
+	}
+}
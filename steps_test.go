@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestSteps(t *testing.T) {
+	a := assert.New(t)
+	now := time.Now()
+
+	var runs []string
+	fail := true
+	f := Steps(
+		Step{Name: "s1", F: func(time.Time) error {
+			runs = append(runs, "s1")
+			return nil
+		}},
+		Step{Name: "s2", F: func(time.Time) error {
+			runs = append(runs, "s2")
+			if fail {
+				return ErrNotReady
+			}
+			return nil
+		}},
+		Step{Name: "s3", F: func(time.Time) error {
+			runs = append(runs, "s3")
+			return nil
+		}},
+	)
+
+	err := f(now)
+	a.Error(err).
+		True(errors.Is(err, ErrNotReady)).
+		Equal(err.Error(), "step s2: "+ErrNotReady.Error()).
+		Equal(runs, []string{"s1", "s2"})
+
+	// 从失败的步骤开始
+	runs = runs[:0]
+	fail = false
+	a.NotError(f(now))
+	a.Equal(runs, []string{"s2", "s3"})
+
+	// 全部完成之后，从头开始
+	runs = runs[:0]
+	a.NotError(f(now))
+	a.Equal(runs, []string{"s1", "s2", "s3"})
+
+	// 步骤中 panic
+	runs = runs[:0]
+	p := Steps(
+		Step{Name: "s1", F: func(time.Time) error {
+			runs = append(runs, "s1")
+			return nil
+		}},
+		Step{Name: "s2", F: func(time.Time) error {
+			runs = append(runs, "s2")
+			if fail {
+				panic("s2")
+			}
+			return nil
+		}},
+	)
+	fail = true
+	a.Panic(func() { p(now) })
+	fail = false
+	a.NotError(p(now))
+	a.Equal(runs, []string{"s1", "s2", "s2"})
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"errors"
+	"time"
+)
+
+// 每天允许执行任务的时间段
+//
+// start 和 end 为距离零点的时长，start 大于 end 表示跨越零点，比如 22:00-02:00。
+type window struct {
+	start, end time.Duration
+}
+
+// OnlyBetween 限定任务只能在每天的 [start,end) 时间段内执行
+//
+// start 和 end 的格式为 15:04，以任务执行时间所在的时区计算，
+// start 大于 end 表示跨越零点，比如 OnlyBetween("22:00", "02:00")。
+// 调度器给出的执行时间，或是因为延迟等原因，实际执行的时间不在该时间段内时，
+// 任务会被推迟到下一个时间段的开始时间执行，而不是直接跳过。
+// start 和 end 都为空时，表示取消限制。
+func (j *Job) OnlyBetween(start, end string) error {
+	var w *window
+	if start != "" || end != "" {
+		s, err := parseClock(start)
+		if err != nil {
+			return err
+		}
+
+		e, err := parseClock(end)
+		if err != nil {
+			return err
+		}
+
+		if s == e {
+			return errors.New("start 与 end 不能相同")
+		}
+		w = &window{start: s, end: e}
+	}
+
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	j.window = w
+	return nil
+}
+
+func parseClock(v string) (time.Duration, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// 判断 t 是否在时间段内
+func (w *window) contains(t time.Time) bool {
+	h, m, s := t.Clock()
+	clock := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	if w.start < w.end {
+		return clock >= w.start && clock < w.end
+	}
+	return clock >= w.start || clock < w.end
+}
+
+// 如果 t 不在时间段内，返回下一个时间段的开始时间，否则原样返回。
+func (w *window) fit(t time.Time) time.Time {
+	if w == nil || t.IsZero() || w.contains(t) {
+		return t
+	}
+
+	year, month, day := t.Date()
+	start := w.startOf(year, month, day, t.Location())
+	if !start.After(t) {
+		start = w.startOf(year, month, day+1, t.Location())
+	}
+	return start
+}
+
+// 返回指定日期中时间段的开始时间
+//
+// 以墙上时间构建，而不是零点加上时长，在夏令时切换的日期中也不会偏差一小时。
+func (w *window) startOf(year int, month time.Month, day int, loc *time.Location) time.Time {
+	h, m := int(w.start/time.Hour), int(w.start%time.Hour/time.Minute)
+	return time.Date(year, month, day, h, m, 0, 0, loc)
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+
+package scheduled
+
+import (
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func TestWindow_fit(t *testing.T) {
+	a := assert.New(t)
+
+	date := func(day, hour, minute int) time.Time {
+		return time.Date(2020, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	var w *window
+	a.Equal(w.fit(date(1, 1, 0)), date(1, 1, 0))
+
+	w = &window{start: 2 * time.Hour, end: 5 * time.Hour}
+	a.Equal(w.fit(date(1, 1, 0)), date(1, 2, 0)).
+		Equal(w.fit(date(1, 2, 0)), date(1, 2, 0)).
+		Equal(w.fit(date(1, 4, 59)), date(1, 4, 59)).
+		Equal(w.fit(date(1, 5, 0)), date(2, 2, 0)).
+		Equal(w.fit(date(1, 23, 0)), date(2, 2, 0))
+	a.True(w.fit(time.Time{}).IsZero())
+
+	// 跨越零点
+	w = &window{start: 22 * time.Hour, end: 2 * time.Hour}
+	a.Equal(w.fit(date(1, 23, 0)), date(1, 23, 0)).
+		Equal(w.fit(date(2, 1, 0)), date(2, 1, 0)).
+		Equal(w.fit(date(2, 2, 0)), date(2, 22, 0)).
+		Equal(w.fit(date(2, 12, 0)), date(2, 22, 0))
+
+	// 夏令时切换的日期
+	loc, err := time.LoadLocation("America/New_York")
+	a.NotError(err).NotNil(loc)
+	w = &window{start: 9 * time.Hour, end: 17 * time.Hour}
+	a.Equal(w.fit(time.Date(2020, 3, 8, 3, 0, 0, 0, loc)), time.Date(2020, 3, 8, 9, 0, 0, 0, loc)).
+		Equal(w.fit(time.Date(2020, 3, 7, 18, 0, 0, 0, loc)), time.Date(2020, 3, 8, 9, 0, 0, 0, loc)).
+		Equal(w.fit(time.Date(2020, 11, 1, 3, 0, 0, 0, loc)), time.Date(2020, 11, 1, 9, 0, 0, 0, loc)).
+		Equal(w.fit(time.Date(2020, 10, 31, 18, 0, 0, 0, loc)), time.Date(2020, 11, 1, 9, 0, 0, 0, loc))
+}
+
+func TestJob_OnlyBetween(t *testing.T) {
+	a := assert.New(t)
+	srv := NewServer(time.UTC, nil, nil)
+	a.NotError(srv.Cron("hourly", succFunc, "@hourly", false))
+	j := srv.jobs[0]
+
+	a.Error(j.OnlyBetween("2:00", "25:00"))
+	a.Error(j.OnlyBetween("a", "05:00"))
+	a.Error(j.OnlyBetween("02:00", "02:00"))
+	a.Nil(j.window)
+
+	a.NotError(j.OnlyBetween("02:00", "05:00"))
+	j.init(time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC))
+	a.Equal(j.Next(), time.Date(2020, 1, 2, 2, 0, 0, 0, time.UTC))
+
+	// 延迟执行时，超出时间段的部分被推迟到下一个时间段
+	a.False(j.start(time.Date(2020, 1, 2, 5, 10, 0, 0, time.UTC)))
+	a.Equal(j.Next(), time.Date(2020, 1, 3, 2, 0, 0, 0, time.UTC))
+
+	a.True(j.start(time.Date(2020, 1, 3, 2, 0, 0, 0, time.UTC)))
+	j.run(PanicRecover, nil, nil, nil, nil)
+	a.Equal(j.Next(), time.Date(2020, 1, 3, 3, 0, 0, 0, time.UTC))
+
+	j.locker.Lock()
+	j.next = time.Date(2020, 1, 3, 4, 0, 0, 0, time.UTC)
+	j.locker.Unlock()
+	times := j.upcoming(time.Date(2020, 1, 4, 4, 0, 0, 0, time.UTC), 10)
+	a.Equal(times, []time.Time{
+		time.Date(2020, 1, 3, 4, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 4, 2, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 4, 3, 0, 0, 0, time.UTC),
+	})
+
+	// 取消限制
+	a.NotError(j.OnlyBetween("", ""))
+	a.Nil(j.window)
+}